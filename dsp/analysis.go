@@ -0,0 +1,246 @@
+package dsp
+
+import (
+	"math"
+
+	"github.com/gordonklaus/dsp/dsp/fft"
+)
+
+// PitchTracker estimates the fundamental frequency of x with the YIN algorithm.
+// It analyses the last 2048 samples every 256 samples, so it tracks pitches down to SampleRate/1024 (about 47Hz at 48kHz),
+// and holds its outputs in between.
+// Freq is the estimated frequency in Hz, or 0 if x is silent;
+// confidence (0..1) is high for clearly periodic signals and low for noisy ones.
+type PitchTracker struct {
+	sampleRate       float32
+	framer           framer
+	plan             *fft.RealPlan
+	buf              []float32
+	head, frame, acc []complex64
+	d                []float64
+	freq, confidence float32
+}
+
+const (
+	pitchTrackerSize      = 2048
+	pitchTrackerHop       = 256
+	pitchTrackerThreshold = .1 // The YIN absolute threshold.
+)
+
+func (p *PitchTracker) Init(c Config) {
+	const n = pitchTrackerSize
+	*p = PitchTracker{
+		sampleRate: c.SampleRate,
+		plan:       fft.NewReal(2 * n),
+		buf:        make([]float32, 2*n),
+		head:       make([]complex64, n+1),
+		frame:      make([]complex64, n+1),
+		acc:        make([]complex64, n+1),
+		d:          make([]float64, n/2),
+	}
+	p.framer.init(n, pitchTrackerHop)
+}
+
+func (p *PitchTracker) Process(x float32) (freq, confidence float32) {
+	if frame := p.framer.next(x); frame != nil {
+		p.analyse(frame)
+	}
+	return p.freq, p.confidence
+}
+
+// analyse computes the cumulative mean normalized difference function of the frame, using the FFT to compute the autocorrelation,
+// and picks its first dip below the threshold.
+func (p *PitchTracker) analyse(x []float32) {
+	w := len(x) / 2 // The integration window.
+
+	// r(τ) = Σ x[j]x[j+τ] for j < w.
+	for i := range p.buf {
+		p.buf[i] = 0
+	}
+	copy(p.buf, x[:w])
+	p.plan.Forward(p.head, p.buf)
+	copy(p.buf, x)
+	p.plan.Forward(p.frame, p.buf)
+	for k := range p.acc {
+		p.acc[k] = complex(real(p.head[k]), -imag(p.head[k])) * p.frame[k]
+	}
+	p.plan.Inverse(p.buf, p.acc)
+
+	// d(τ) = Σ (x[j]-x[j+τ])² = e(0) + e(τ) - 2r(τ), where e(τ) = Σ x[j+τ]².
+	e0 := 0.
+	for _, v := range x[:w] {
+		e0 += float64(v) * float64(v)
+	}
+	if e0 < 1e-10 {
+		p.freq, p.confidence = 0, 0
+		return
+	}
+	e := e0
+	sum := 0.
+	p.d[0] = 1
+	for tau := 1; tau < len(p.d); tau++ {
+		e += float64(x[tau+w-1])*float64(x[tau+w-1]) - float64(x[tau-1])*float64(x[tau-1])
+		d := math.Max(0, e0+e-2*float64(p.buf[tau]))
+		sum += d
+		p.d[tau] = 1
+		if sum > 0 {
+			p.d[tau] = d * float64(tau) / sum
+		}
+	}
+
+	best := 0
+	for tau := 2; tau < len(p.d)-1; tau++ {
+		if p.d[tau] < pitchTrackerThreshold {
+			for tau+1 < len(p.d)-1 && p.d[tau+1] < p.d[tau] {
+				tau++
+			}
+			best = tau
+			break
+		}
+		if best == 0 || p.d[tau] < p.d[best] {
+			best = tau
+		}
+	}
+
+	// Refine the period by parabolic interpolation.
+	a, b, c := p.d[best-1], p.d[best], p.d[best+1]
+	period := float64(best)
+	if den := a - 2*b + c; den > 0 {
+		period += (a - c) / (2 * den)
+	}
+	p.freq = float32(float64(p.sampleRate) / period)
+	p.confidence = float32(clamp(1-b, 0, 1))
+}
+
+// OnsetDetector detects the onsets of notes and other events in x by spectral flux:
+// the increase in (log-compressed) magnitude summed over all frequencies from one 1024-sample frame to the next, 256 samples later.
+// Onset is 1 for one sample when flux peaks above threshold times its recent average, and at least 50ms after the previous onset;
+// it is 0 otherwise.
+// Flux is the detection function itself, which is held between frames.
+type OnsetDetector struct {
+	sampleRate     float32
+	framer         framer
+	plan           *fft.RealPlan
+	window, buf    []float32
+	spectrum       []complex64
+	mag            []float32
+	flux, prevFlux float32
+	rising         bool
+	average        float32
+	sinceOnset     int
+}
+
+const (
+	onsetSize     = 1024
+	onsetHop      = 256
+	onsetAverage  = .5  // The time constant in seconds of the average flux.
+	onsetInterval = .05 // The minimum time in seconds between onsets.
+	onsetFloor    = .01 // The minimum flux of an onset, so that noise in silence is ignored.
+)
+
+func (o *OnsetDetector) Init(c Config) {
+	*o = OnsetDetector{
+		sampleRate: c.SampleRate,
+		plan:       fft.NewReal(onsetSize),
+		window:     make([]float32, onsetSize),
+		buf:        make([]float32, onsetSize),
+		spectrum:   make([]complex64, onsetSize/2+1),
+		mag:        make([]float32, onsetSize/2+1),
+		sinceOnset: int(onsetInterval * c.SampleRate),
+	}
+	o.framer.init(onsetSize, onsetHop)
+	sum := float32(0)
+	for i := range o.window {
+		o.window[i] = float32(.5 - .5*math.Cos(2*math.Pi*float64(i)/onsetSize))
+		sum += o.window[i]
+	}
+	// Scale the window so that a full-scale sinusoid has magnitude 1.
+	for i := range o.window {
+		o.window[i] *= 2 / sum
+	}
+}
+
+func (o *OnsetDetector) Process(x, threshold float32) (onset, flux float32) {
+	o.sinceOnset++
+	frame := o.framer.next(x)
+	if frame == nil {
+		return 0, o.flux
+	}
+	for i, v := range frame {
+		o.buf[i] = v * o.window[i]
+	}
+	o.plan.Forward(o.spectrum, o.buf)
+	o.prevFlux = o.flux
+	o.flux = 0
+	for k, v := range o.spectrum {
+		m := float32(math.Log1p(100 * math.Hypot(float64(real(v)), float64(imag(v)))))
+		o.flux += max(0, m-o.mag[k])
+		o.mag[k] = m
+	}
+	o.flux /= float32(len(o.spectrum))
+
+	// The previous frame is an onset if it was a peak of the flux.
+	rising := o.flux > o.prevFlux
+	if o.rising && !rising && o.prevFlux > threshold*o.average && o.prevFlux > onsetFloor &&
+		float32(o.sinceOnset) > onsetInterval*o.sampleRate {
+		onset = 1
+		o.sinceOnset = 0
+	}
+	o.rising = rising
+	o.average += onePoleCoef(onsetAverage, o.sampleRate/onsetHop) * (o.prevFlux - o.average)
+	return onset, o.flux
+}
+
+// ZeroCrossingRate measures the number of times per second that x changes sign, averaged with time constant time (in seconds).
+// It is twice the frequency of a sinusoid and high for noisy signals.
+type ZeroCrossingRate struct {
+	sampleRate float32
+	positive   bool
+	time, coef float32
+	rate       float32
+}
+
+func (z *ZeroCrossingRate) Init(c Config) {
+	*z = ZeroCrossingRate{sampleRate: c.SampleRate, coef: 1}
+}
+
+func (z *ZeroCrossingRate) Process(x, time float32) float32 {
+	if time != z.time {
+		z.time = time
+		z.coef = onePoleCoef(time, z.sampleRate)
+	}
+	positive := x > 0
+	crossing := float32(0)
+	if positive != z.positive {
+		crossing = z.sampleRate
+	}
+	z.positive = positive
+	z.rate += z.coef * (crossing - z.rate)
+	return z.rate
+}
+
+// framer collects the last size samples of a signal and returns them every hop samples.
+type framer struct {
+	in  []float32
+	hop int
+	pos int
+}
+
+func (f *framer) init(size, hop int) {
+	*f = framer{in: make([]float32, size), hop: hop}
+}
+
+// next adds x to the input and returns the latest frame if it is time for one, or nil.
+// The frame is only valid until the next call.
+func (f *framer) next(x float32) []float32 {
+	if f.pos == f.hop {
+		f.pos = 0
+		copy(f.in, f.in[f.hop:])
+	}
+	f.in[len(f.in)-f.hop+f.pos] = x
+	f.pos++
+	if f.pos == f.hop {
+		return f.in
+	}
+	return nil
+}
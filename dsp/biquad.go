@@ -0,0 +1,225 @@
+package dsp
+
+import "math"
+
+// LowPass is a second-order low-pass filter.
+type LowPass struct {
+	rbj
+}
+
+func (f *LowPass) Init(c Config) {
+	f.rbj.init(c)
+}
+
+func (f *LowPass) Process(x, freq, q float32) float32 {
+	f.update(lowPass, freq, q, 0)
+	return f.process(x)
+}
+
+// HighPass is a second-order high-pass filter.
+type HighPass struct {
+	rbj
+}
+
+func (f *HighPass) Init(c Config) {
+	f.rbj.init(c)
+}
+
+func (f *HighPass) Process(x, freq, q float32) float32 {
+	f.update(highPass, freq, q, 0)
+	return f.process(x)
+}
+
+// BandPass is a second-order band-pass filter with unity gain at the center frequency.
+type BandPass struct {
+	rbj
+}
+
+func (f *BandPass) Init(c Config) {
+	f.rbj.init(c)
+}
+
+func (f *BandPass) Process(x, freq, q float32) float32 {
+	f.update(bandPass, freq, q, 0)
+	return f.process(x)
+}
+
+// Notch is a second-order band-stop filter.
+type Notch struct {
+	rbj
+}
+
+func (f *Notch) Init(c Config) {
+	f.rbj.init(c)
+}
+
+func (f *Notch) Process(x, freq, q float32) float32 {
+	f.update(notch, freq, q, 0)
+	return f.process(x)
+}
+
+// AllPass is a second-order all-pass filter.
+type AllPass struct {
+	rbj
+}
+
+func (f *AllPass) Init(c Config) {
+	f.rbj.init(c)
+}
+
+func (f *AllPass) Process(x, freq, q float32) float32 {
+	f.update(allPass, freq, q, 0)
+	return f.process(x)
+}
+
+// Peak is a peaking equalizer. Gain is in decibels.
+type Peak struct {
+	rbj
+}
+
+func (f *Peak) Init(c Config) {
+	f.rbj.init(c)
+}
+
+func (f *Peak) Process(x, freq, q, gain float32) float32 {
+	f.update(peak, freq, q, gain)
+	return f.process(x)
+}
+
+// LowShelf is a low shelving equalizer. Gain is in decibels.
+type LowShelf struct {
+	rbj
+}
+
+func (f *LowShelf) Init(c Config) {
+	f.rbj.init(c)
+}
+
+func (f *LowShelf) Process(x, freq, q, gain float32) float32 {
+	f.update(lowShelf, freq, q, gain)
+	return f.process(x)
+}
+
+// HighShelf is a high shelving equalizer. Gain is in decibels.
+type HighShelf struct {
+	rbj
+}
+
+func (f *HighShelf) Init(c Config) {
+	f.rbj.init(c)
+}
+
+func (f *HighShelf) Process(x, freq, q, gain float32) float32 {
+	f.update(highShelf, freq, q, gain)
+	return f.process(x)
+}
+
+type rbjType int
+
+const (
+	lowPass rbjType = iota
+	highPass
+	bandPass
+	notch
+	allPass
+	peak
+	lowShelf
+	highShelf
+)
+
+// rbj is a biquad whose coefficients follow Robert Bristow-Johnson's Audio EQ Cookbook.
+type rbj struct {
+	biquad
+	sampleRate    float32
+	freq, q, gain float32
+	designed      bool
+}
+
+func (f *rbj) init(c Config) {
+	*f = rbj{sampleRate: c.SampleRate}
+}
+
+// update recomputes the coefficients if the parameters have changed.
+func (f *rbj) update(typ rbjType, freq, q, gain float32) {
+	if f.designed && freq == f.freq && q == f.q && gain == f.gain {
+		return
+	}
+	f.designed = true
+	f.freq, f.q, f.gain = freq, q, gain
+	f.biquad.design(typ, f.sampleRate, freq, q, gain)
+}
+
+// A biquad is a second-order IIR filter in transposed direct form II.
+type biquad struct {
+	b0, b1, b2, a1, a2 float32
+	z1, z2             float32
+}
+
+func (f *biquad) process(x float32) float32 {
+	y := f.b0*x + f.z1
+	f.z1 = f.b1*x - f.a1*y + f.z2
+	f.z2 = f.b2*x - f.a2*y
+	return y
+}
+
+// design sets the coefficients, leaving the filter state intact.
+func (f *biquad) design(typ rbjType, sampleRate, freq, q, gain float32) {
+	fc := clamp(float64(freq), 1e-3, .499*float64(sampleRate))
+	w0 := 2 * math.Pi * fc / float64(sampleRate)
+	sin, cos := math.Sincos(w0)
+	alpha := sin / (2 * math.Max(float64(q), 1e-3))
+	A := math.Pow(10, float64(gain)/40)
+
+	var b0, b1, b2, a0, a1, a2 float64
+	switch typ {
+	case lowPass:
+		b0, b1, b2 = (1-cos)/2, 1-cos, (1-cos)/2
+		a0, a1, a2 = 1+alpha, -2*cos, 1-alpha
+	case highPass:
+		b0, b1, b2 = (1+cos)/2, -(1 + cos), (1+cos)/2
+		a0, a1, a2 = 1+alpha, -2*cos, 1-alpha
+	case bandPass:
+		b0, b1, b2 = alpha, 0, -alpha
+		a0, a1, a2 = 1+alpha, -2*cos, 1-alpha
+	case notch:
+		b0, b1, b2 = 1, -2*cos, 1
+		a0, a1, a2 = 1+alpha, -2*cos, 1-alpha
+	case allPass:
+		b0, b1, b2 = 1-alpha, -2*cos, 1+alpha
+		a0, a1, a2 = 1+alpha, -2*cos, 1-alpha
+	case peak:
+		b0, b1, b2 = 1+alpha*A, -2*cos, 1-alpha*A
+		a0, a1, a2 = 1+alpha/A, -2*cos, 1-alpha/A
+	case lowShelf:
+		s := 2 * math.Sqrt(A) * alpha
+		b0 = A * ((A + 1) - (A-1)*cos + s)
+		b1 = 2 * A * ((A - 1) - (A+1)*cos)
+		b2 = A * ((A + 1) - (A-1)*cos - s)
+		a0 = (A + 1) + (A-1)*cos + s
+		a1 = -2 * ((A - 1) + (A+1)*cos)
+		a2 = (A + 1) + (A-1)*cos - s
+	case highShelf:
+		s := 2 * math.Sqrt(A) * alpha
+		b0 = A * ((A + 1) + (A-1)*cos + s)
+		b1 = -2 * A * ((A - 1) + (A+1)*cos)
+		b2 = A * ((A + 1) + (A-1)*cos - s)
+		a0 = (A + 1) - (A-1)*cos + s
+		a1 = 2 * ((A - 1) - (A+1)*cos)
+		a2 = (A + 1) - (A-1)*cos - s
+	}
+	f.b0 = float32(b0 / a0)
+	f.b1 = float32(b1 / a0)
+	f.b2 = float32(b2 / a0)
+	f.a1 = float32(a1 / a0)
+	f.a2 = float32(a2 / a0)
+}
+
+func clamp(x, min, max float64) float64 {
+	if x < min {
+		return min
+	}
+	if x > max {
+		return max
+	}
+	return x
+}
@@ -0,0 +1,137 @@
+package dsp
+
+import "math"
+
+// SampleHold samples x each time trigger rises above 0 and holds it until the next trigger.
+type SampleHold struct {
+	trigger bool
+	y       float32
+}
+
+func (s *SampleHold) Init(c Config) {
+	*s = SampleHold{}
+}
+
+func (s *SampleHold) Process(x, trigger float32) float32 {
+	on := trigger > 0
+	if on && !s.trigger {
+		s.y = x
+	}
+	s.trigger = on
+	return s.y
+}
+
+// Slew limits the rate at which its output follows x.
+// Rise and fall are the maximum rates of change in units per second; a rate of 0 or less is unlimited.
+type Slew struct {
+	sampleRate float32
+	y          float32
+}
+
+func (s *Slew) Init(c Config) {
+	*s = Slew{sampleRate: c.SampleRate}
+}
+
+func (s *Slew) Process(x, rise, fall float32) float32 {
+	d := x - s.y
+	if up := rise / s.sampleRate; d > up && rise > 0 {
+		d = up
+	}
+	if down := -fall / s.sampleRate; d < down && fall > 0 {
+		d = down
+	}
+	s.y += d
+	return s.y
+}
+
+// Quantizer rounds x to the nearest multiple of step.
+// It has a little hysteresis so that a noisy input near a boundary does not make the output chatter.
+type Quantizer struct {
+	y float32
+}
+
+// quantizerHysteresis is the fraction of a step by which the input must pass a boundary to change the output.
+const quantizerHysteresis = .05
+
+func (q *Quantizer) Init(c Config) {
+	*q = Quantizer{}
+}
+
+func (q *Quantizer) Process(x, step float32) float32 {
+	if step <= 0 {
+		q.y = x
+		return x
+	}
+	q.y = quantize(x/step, round(q.y/step)) * step
+	return q.y
+}
+
+// PitchQuantizer rounds freq (in Hz) to the nearest equal-tempered semitone, with A4 at 440Hz.
+// Like Quantizer, it has a little hysteresis.
+type PitchQuantizer struct {
+	note float32
+}
+
+func (q *PitchQuantizer) Init(c Config) {
+	*q = PitchQuantizer{}
+}
+
+func (q *PitchQuantizer) Process(freq float32) float32 {
+	if freq <= 0 {
+		return 0
+	}
+	note := float32(12 * math.Log2(float64(freq)/440))
+	q.note = quantize(note, q.note)
+	return float32(440 * math.Exp2(float64(q.note)/12))
+}
+
+// quantize rounds x to an integer, keeping the previous integer y unless x is beyond the hysteresis margin.
+func quantize(x, y float32) float32 {
+	if abs(x-y) < .5+quantizerHysteresis {
+		return y
+	}
+	return round(x)
+}
+
+func round(x float32) float32 {
+	return float32(math.Floor(float64(x) + .5))
+}
+
+// Smoother is a one-pole low-pass filter for removing steps ("zipper noise") from control signals.
+// Time is the time constant in seconds.
+type Smoother struct {
+	sampleRate float32
+	time       float32
+	coef       float32
+	y          float32
+}
+
+func (s *Smoother) Init(c Config) {
+	*s = Smoother{sampleRate: c.SampleRate}
+	s.coef = 1
+}
+
+func (s *Smoother) Process(x, time float32) float32 {
+	if time != s.time {
+		s.time = time
+		s.coef = onePoleCoef(time, s.sampleRate)
+	}
+	s.y += s.coef * (x - s.y)
+	return s.y
+}
+
+// DCBlocker removes the DC offset from x with a first-order high-pass filter at 10Hz.
+type DCBlocker struct {
+	r      float32
+	x1, y1 float32
+}
+
+func (f *DCBlocker) Init(c Config) {
+	*f = DCBlocker{r: float32(math.Exp(-2 * math.Pi * 10 / float64(c.SampleRate)))}
+}
+
+func (f *DCBlocker) Process(x float32) float32 {
+	y := x - f.x1 + f.r*f.y1
+	f.x1, f.y1 = x, y
+	return y
+}
@@ -0,0 +1,121 @@
+package dsp
+
+import "github.com/gordonklaus/dsp/dsp/fft"
+
+// Convolver convolves x with an impulse response (IR) using uniformly partitioned FFT convolution,
+// so that long IRs, such as those of reverberant spaces, can be used in real time.
+// Its output is delayed by Latency samples.
+//
+// IR and IRRate (its sample rate) may be set, for example by Load, before Init.
+// Otherwise, if File is set, Init loads the IR from it, panicking if it cannot be read.
+// Init resamples the IR if IRRate differs from the sample rate, preserving its frequency response.
+type Convolver struct {
+	File   string
+	IR     []float32
+	IRRate float32
+
+	plan    *fft.RealPlan
+	h       [][]complex64 // spectra of the IR partitions
+	x       [][]complex64 // spectra of recent input blocks, a ring indexed by xi
+	xi      int
+	acc     []complex64
+	in, out []float32
+	buf     []float32
+	pos     int
+}
+
+// convolverBlock is the partition size of a Convolver.
+const convolverBlock = 256
+
+// Load reads the impulse response from a WAV file, mixing multiple channels down to mono.
+func (c *Convolver) Load(name string) error {
+	ir, rate, err := readWAV(name)
+	if err != nil {
+		return err
+	}
+	c.IR, c.IRRate = ir, rate
+	return nil
+}
+
+func (c *Convolver) Init(cfg Config) {
+	if c.IR == nil && c.File != "" {
+		if err := c.Load(c.File); err != nil {
+			panic(err)
+		}
+	}
+	const b = convolverBlock
+	ir := resample(c.IR, c.IRRate, cfg.SampleRate)
+	if c.IRRate > 0 && c.IRRate != cfg.SampleRate {
+		// Resampling changes the number of samples per unit time, and so the gain of the convolution.
+		g := c.IRRate / cfg.SampleRate
+		for i := range ir {
+			ir[i] *= g
+		}
+	}
+	parts := (len(ir) + b - 1) / b
+
+	c.plan = fft.NewReal(2 * b)
+	c.h = make([][]complex64, parts)
+	c.x = make([][]complex64, parts)
+	c.xi = 0
+	c.acc = make([]complex64, b+1)
+	c.in = make([]float32, 2*b)
+	c.out = make([]float32, b)
+	c.buf = make([]float32, 2*b)
+	c.pos = 0
+	for p := range c.h {
+		for i := range c.buf {
+			c.buf[i] = 0
+		}
+		copy(c.buf[:b], ir[p*b:])
+		c.h[p] = make([]complex64, b+1)
+		c.plan.Forward(c.h[p], c.buf)
+		c.x[p] = make([]complex64, b+1)
+	}
+}
+
+// Latency returns the delay in samples between input and output.
+func (c *Convolver) Latency() int { return convolverBlock }
+
+func (c *Convolver) Process(x float32) float32 {
+	if len(c.h) == 0 {
+		return 0
+	}
+	c.in[convolverBlock+c.pos] = x
+	y := c.out[c.pos]
+	c.pos++
+	if c.pos == convolverBlock {
+		c.pos = 0
+		c.processBlock()
+	}
+	return y
+}
+
+// processBlock computes the next output block by overlap-save, multiplying the spectrum of each of the recent input blocks
+// with that of the corresponding IR partition.
+func (c *Convolver) processBlock() {
+	const b = convolverBlock
+	c.xi--
+	if c.xi < 0 {
+		c.xi = len(c.x) - 1
+	}
+	c.plan.Forward(c.x[c.xi], c.in)
+	copy(c.in, c.in[b:])
+
+	for k := range c.acc {
+		c.acc[k] = 0
+	}
+	xi := c.xi
+	for _, h := range c.h {
+		x := c.x[xi]
+		for k := range c.acc {
+			c.acc[k] += x[k] * h[k]
+		}
+		xi++
+		if xi == len(c.x) {
+			xi = 0
+		}
+	}
+	c.plan.Inverse(c.buf, c.acc)
+	copy(c.out, c.buf[b:])
+}
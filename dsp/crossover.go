@@ -0,0 +1,133 @@
+package dsp
+
+import "github.com/gordonklaus/dsp/dsp/iir"
+
+// Crossover2 splits x into two bands with a Linkwitz-Riley crossover at freq (in Hz), whose low- and high-pass filters are each a Butterworth filter
+// of half the order applied twice, so that they are both 6dB down at the crossover frequency and the bands sum to an allpass response.
+// Order must be set before Init: it is 2, 4 or 8 (LR2, LR4 or LR8; 0 is taken to be 4).
+// In LR2 (and other orders that are not multiples of 4), the high band is inverted so that the bands sum flat.
+type Crossover2 struct {
+	Order int
+
+	split linkwitzRiley
+}
+
+func (f *Crossover2) Init(c Config) {
+	f.split.init(c, f.Order)
+}
+
+func (f *Crossover2) Process(x, freq float32) (low, high float32) {
+	f.split.update(freq)
+	return f.split.process(x)
+}
+
+// Crossover3 splits x into three bands with Linkwitz-Riley crossovers at lowFreq and highFreq (in Hz), like Crossover2.
+// The low band is passed through an allpass filter matching the phase of the upper crossover so that the bands sum flat.
+type Crossover3 struct {
+	Order int
+
+	low, high linkwitzRiley
+	allpass   linkwitzRiley
+}
+
+func (f *Crossover3) Init(c Config) {
+	f.low.init(c, f.Order)
+	f.high.init(c, f.Order)
+	f.allpass.init(c, f.Order)
+}
+
+func (f *Crossover3) Process(x, lowFreq, highFreq float32) (low, mid, high float32) {
+	f.low.update(lowFreq)
+	f.high.update(highFreq)
+	f.allpass.update(highFreq)
+	low, rest := f.low.process(x)
+	mid, high = f.high.process(rest)
+	return f.allpass.allpass(low), mid, high
+}
+
+// Crossover4 splits x into four bands with Linkwitz-Riley crossovers at lowFreq, midFreq and highFreq (in Hz, in increasing order), like Crossover3.
+type Crossover4 struct {
+	Order int
+
+	splits   [3]linkwitzRiley
+	allpass1 [2]linkwitzRiley // for the lowest band, at midFreq and highFreq
+	allpass2 linkwitzRiley    // for the second band, at highFreq
+}
+
+func (f *Crossover4) Init(c Config) {
+	for i := range f.splits {
+		f.splits[i].init(c, f.Order)
+	}
+	for i := range f.allpass1 {
+		f.allpass1[i].init(c, f.Order)
+	}
+	f.allpass2.init(c, f.Order)
+}
+
+func (f *Crossover4) Process(x, lowFreq, midFreq, highFreq float32) (low, lowMid, highMid, high float32) {
+	f.splits[0].update(lowFreq)
+	f.splits[1].update(midFreq)
+	f.splits[2].update(highFreq)
+	f.allpass1[0].update(midFreq)
+	f.allpass1[1].update(highFreq)
+	f.allpass2.update(highFreq)
+	low, rest := f.splits[0].process(x)
+	lowMid, rest = f.splits[1].process(rest)
+	highMid, high = f.splits[2].process(rest)
+	low = f.allpass1[1].allpass(f.allpass1[0].allpass(low))
+	lowMid = f.allpass2.allpass(lowMid)
+	return low, lowMid, highMid, high
+}
+
+// linkwitzRiley is a Linkwitz-Riley crossover.
+type linkwitzRiley struct {
+	lp, hp     cascade
+	invert     bool
+	sampleRate float32
+	order      int
+	freq       float32
+	designed   bool
+}
+
+func (f *linkwitzRiley) init(c Config, order int) {
+	if order <= 0 {
+		order = 4
+	}
+	*f = linkwitzRiley{sampleRate: c.SampleRate, order: order}
+}
+
+// update redesigns the filters if freq has changed, keeping their state.
+func (f *linkwitzRiley) update(freq float32) {
+	if f.designed && freq == f.freq {
+		return
+	}
+	f.freq = freq
+	n := (f.order + 1) / 2
+	p := iir.Butterworth(n)
+	fc := clamp(float64(freq/f.sampleRate), 1e-5, .499)
+	lp := iir.LowPass(p, fc)
+	hp := iir.HighPass(p, fc)
+	if f.designed {
+		f.lp.setCoefficients(append(lp, lp...))
+		f.hp.setCoefficients(append(hp, hp...))
+	} else {
+		f.lp.setSections(append(lp, lp...))
+		f.hp.setSections(append(hp, hp...))
+		f.designed = true
+	}
+	f.invert = n%2 == 1
+}
+
+func (f *linkwitzRiley) process(x float32) (low, high float32) {
+	low, high = f.lp.process(x), f.hp.process(x)
+	if f.invert {
+		high = -high
+	}
+	return low, high
+}
+
+// allpass returns the sum of the bands, which has the same phase response as a band split by another linkwitzRiley of the same design.
+func (f *linkwitzRiley) allpass(x float32) float32 {
+	low, high := f.process(x)
+	return low + high
+}
@@ -0,0 +1,207 @@
+package dsp
+
+import "math"
+
+// Compressor reduces the level of x while sidechain is above threshold.
+// Connect x to sidechain for ordinary (non-sidechain) compression.
+// Threshold, knee and gainReduction are in decibels; attack and release are in seconds.
+type Compressor struct {
+	dynamics
+}
+
+func (d *Compressor) Init(c Config) {
+	d.dynamics.init(c)
+}
+
+func (d *Compressor) Process(x, sidechain, threshold, ratio, attack, release, knee float32) (y, gainReduction float32) {
+	level := d.detect(sidechain, attack, release)
+	return attenuate(x, compressGain(level, threshold, 1-1/max(ratio, 1), knee))
+}
+
+// Limiter is a compressor with infinite ratio that delays x by lookahead seconds (up to limiterMaxLookahead)
+// so that gain reduction is already in place when a peak in sidechain arrives.
+// The gain reduction follows the peak level of sidechain over the lookahead, ramping up over the lookahead and
+// falling with the release time, so that if x is connected to sidechain, the output never exceeds threshold.
+// Connect x to sidechain for ordinary (non-sidechain) limiting.
+// Threshold, knee and gainReduction are in decibels; lookahead and release are in seconds.
+type Limiter struct {
+	sampleRate  float32
+	t           int       // the number of samples processed
+	x, peak     []float32 // rings of recent input and sidechain peaks, indexed by t
+	gr          []float32 // a ring of recent target gain reductions
+	grSum       float64   // the sum of the last n+1 target gain reductions
+	n           int       // the lookahead in samples
+	maxima      []int     // a ring of the times of the decreasing maxima of the peaks in the lookahead window
+	head, count int
+	level       float32 // the smoothed gain reduction
+}
+
+const limiterMaxLookahead = .1
+
+func (d *Limiter) Init(c Config) {
+	size := int(limiterMaxLookahead*c.SampleRate) + 2
+	*d = Limiter{
+		sampleRate: c.SampleRate,
+		x:          make([]float32, size),
+		peak:       make([]float32, size),
+		gr:         make([]float32, size),
+		maxima:     make([]int, size),
+	}
+}
+
+func (d *Limiter) Process(x, sidechain, threshold, lookahead, release, knee float32) (y, gainReduction float32) {
+	size := len(d.x)
+	n := int(clamp(float64(lookahead*d.sampleRate), 0, float64(size-2)) + .5)
+	t := d.t
+	d.t++
+	i := t % size
+	d.x[i] = x
+	d.peak[i] = abs(sidechain)
+
+	// The maximum of the peaks over the last n+1 samples, by a monotonic queue.
+	for d.count > 0 && d.peak[d.maxima[(d.head+d.count-1)%size]%size] <= d.peak[i] {
+		d.count--
+	}
+	d.maxima[(d.head+d.count)%size] = t
+	d.count++
+	for d.maxima[d.head] < t-n {
+		d.head = (d.head + 1) % size
+		d.count--
+	}
+	d.gr[i] = compressGain(decibels(d.peak[d.maxima[d.head]%size]), threshold, 1, knee)
+
+	// Each of the last n+1 target gain reductions is at least that required by the delayed sample, and so is their average.
+	if n != d.n {
+		d.n = n
+		d.grSum = 0
+		for k := 0; k <= n; k++ {
+			d.grSum += float64(d.gr[(t-k+size)%size])
+		}
+	} else {
+		d.grSum += float64(d.gr[i]) - float64(d.gr[(t-n-1+size)%size])
+	}
+	gr := float32(d.grSum / float64(n+1))
+	if gr > d.level {
+		d.level = gr
+	} else {
+		d.level += (gr - d.level) * onePoleCoef(release, d.sampleRate)
+	}
+	return attenuate(d.x[(t-n+size)%size], d.level)
+}
+
+// Expander reduces the level of x by ratio while sidechain is below threshold.
+// Connect x to sidechain for ordinary (non-sidechain) expansion.
+// Threshold, knee and gainReduction are in decibels; attack and release are in seconds.
+type Expander struct {
+	dynamics
+}
+
+func (d *Expander) Init(c Config) {
+	d.dynamics.init(c)
+}
+
+func (d *Expander) Process(x, sidechain, threshold, ratio, attack, release, knee float32) (y, gainReduction float32) {
+	level := d.detect(sidechain, attack, release)
+	return attenuate(x, expandGain(level, threshold, max(ratio, 1)-1, knee))
+}
+
+// Gate attenuates x by depth decibels while sidechain is below threshold.
+// Connect x to sidechain for ordinary (non-sidechain) gating.
+// Threshold, depth, knee and gainReduction are in decibels; attack and release are in seconds.
+type Gate struct {
+	dynamics
+}
+
+func (d *Gate) Init(c Config) {
+	d.dynamics.init(c)
+}
+
+func (d *Gate) Process(x, sidechain, threshold, depth, attack, release, knee float32) (y, gainReduction float32) {
+	level := d.detect(sidechain, attack, release)
+	gr := expandGain(level, threshold, gateSlope, knee)
+	if gr > depth {
+		gr = depth
+	}
+	return attenuate(x, gr)
+}
+
+// gateSlope is the expansion slope (ratio-1) used by Gate; large enough to act as a switch.
+const gateSlope = 1000
+
+// maxGainReduction bounds the gain reduction and the range of detected levels.
+const maxGainReduction = 120
+
+type dynamics struct {
+	sampleRate float32
+	envelope   float32
+}
+
+func (d *dynamics) init(c Config) {
+	*d = dynamics{sampleRate: c.SampleRate}
+}
+
+// detect returns the level of sidechain in decibels, from an envelope follower that rises with the attack time and falls with the release time.
+func (d *dynamics) detect(sidechain, attack, release float32) float32 {
+	x := abs(sidechain)
+	t := release
+	if x > d.envelope {
+		t = attack
+	}
+	d.envelope += (x - d.envelope) * onePoleCoef(t, d.sampleRate)
+	return decibels(d.envelope)
+}
+
+// attenuate attenuates x by gr decibels.
+func attenuate(x, gr float32) (y, gainReduction float32) {
+	if gr > maxGainReduction {
+		gr = maxGainReduction
+	}
+	return x * float32(math.Pow(10, float64(-gr)/20)), gr
+}
+
+// compressGain returns the gain reduction in decibels of a soft-knee compressor at input level x (dB).
+// Slope is 1-1/ratio.
+func compressGain(x, threshold, slope, knee float32) float32 {
+	if knee < 0 {
+		knee = 0
+	}
+	d := x - threshold
+	switch {
+	case 2*d <= -knee:
+		return 0
+	case 2*d < knee:
+		d += knee / 2
+		return slope * d * d / (2 * knee)
+	}
+	return slope * d
+}
+
+// expandGain returns the gain reduction in decibels of a soft-knee downward expander at input level x (dB).
+// Slope is ratio-1.
+func expandGain(x, threshold, slope, knee float32) float32 {
+	return compressGain(-x, -threshold, slope, knee)
+}
+
+// onePoleCoef returns the coefficient of a one-pole smoother with time constant t seconds.
+func onePoleCoef(t, sampleRate float32) float32 {
+	if n := t * sampleRate; n > 0 {
+		return float32(-math.Expm1(-1 / float64(n)))
+	}
+	return 1
+}
+
+// decibels returns the level of x in decibels, floored at -maxGainReduction.
+func decibels(x float32) float32 {
+	x = abs(x)
+	if x < 1e-6 {
+		return -maxGainReduction
+	}
+	return float32(20 * math.Log10(float64(x)))
+}
+
+func max(x, y float32) float32 {
+	if x > y {
+		return x
+	}
+	return y
+}
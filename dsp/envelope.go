@@ -0,0 +1,162 @@
+package dsp
+
+import "math"
+
+// ADSR is an attack-decay-sustain-release envelope generator.
+// The envelope is triggered when gate rises above 0 and released when it falls back.
+// Times are in seconds; sustain is a level from 0 to 1.
+type ADSR struct {
+	sampleRate   float32
+	gate         bool
+	stage        adsrStage
+	level        float32
+	releaseLevel float32
+}
+
+type adsrStage int
+
+const (
+	adsrIdle adsrStage = iota
+	adsrAttack
+	adsrDecay
+	adsrSustain
+	adsrRelease
+)
+
+func (e *ADSR) Init(c Config) {
+	*e = ADSR{sampleRate: c.SampleRate}
+}
+
+func (e *ADSR) Process(gate, attack, decay, sustain, release float32) float32 {
+	sustain = float32(clamp(float64(sustain), 0, 1))
+	on := gate > 0
+	if on && !e.gate {
+		e.stage = adsrAttack
+	} else if !on && e.gate {
+		e.stage = adsrRelease
+		e.releaseLevel = e.level
+	}
+	e.gate = on
+
+	switch e.stage {
+	case adsrAttack:
+		e.level += e.step(attack)
+		if e.level >= 1 {
+			e.level = 1
+			e.stage = adsrDecay
+		}
+	case adsrDecay:
+		e.level -= (1 - sustain) * e.step(decay)
+		if e.level <= sustain {
+			e.level = sustain
+			e.stage = adsrSustain
+		}
+	case adsrSustain:
+		e.level = sustain
+	case adsrRelease:
+		e.level -= e.releaseLevel * e.step(release)
+		if e.level <= 0 {
+			e.level = 0
+			e.stage = adsrIdle
+		}
+	}
+	return e.level
+}
+
+// step returns the per-sample increment of a unit ramp lasting t seconds.
+func (e *ADSR) step(t float32) float32 {
+	if n := t * e.sampleRate; n > 1 {
+		return 1 / n
+	}
+	return 1
+}
+
+// A Segment is one stage of an Envelope.
+type Segment struct {
+	// Level is the level reached at the end of the segment.
+	Level float32
+
+	// Time is the duration of the segment in seconds.
+	Time float32
+
+	// Curve is 0 for a linear segment. Positive values give an exponential curve that moves quickly at first;
+	// negative values one that moves slowly at first.
+	Curve float32
+}
+
+// Envelope is a multi-segment envelope generator.
+// A rising gate starts the first segment from the current level.
+// If Sustain is the index of a segment, the envelope holds at the end of that segment while the gate is high and
+// continues with the next segment when the gate falls; if Sustain is negative, the segments run to completion regardless of the gate.
+// After the last segment the envelope holds its final level.
+//
+// Segments and Sustain may be set before Init.
+// Otherwise, Init uses an attack to 1 over 10ms, a decay to 0.5 over 200ms, at whose end it sustains, and a release to 0 over 300ms.
+// When setting Segments, note that Sustain 0 holds at the end of the first segment; set it to -1 to not sustain.
+type Envelope struct {
+	Segments []Segment
+	Sustain  int
+
+	sampleRate  float32
+	gate        bool
+	seg         int
+	i, n        int
+	from, level float32
+}
+
+func (e *Envelope) Init(c Config) {
+	if len(e.Segments) == 0 {
+		e.Segments = []Segment{
+			{Level: 1, Time: .01},
+			{Level: .5, Time: .2},
+			{Level: 0, Time: .3},
+		}
+		e.Sustain = 1
+	}
+	e.sampleRate = c.SampleRate
+	e.gate = false
+	e.seg = -1
+	e.level = 0
+}
+
+func (e *Envelope) Process(gate float32) float32 {
+	on := gate > 0
+	if on && !e.gate {
+		e.start(0)
+	} else if !on && e.gate && e.seg <= e.Sustain {
+		e.start(e.Sustain + 1)
+	}
+	e.gate = on
+
+	for e.seg >= 0 && e.seg < len(e.Segments) {
+		s := e.Segments[e.seg]
+		if e.i < e.n {
+			e.i++
+			e.level = curve(e.from, s.Level, float32(e.i)/float32(e.n), s.Curve)
+			break
+		}
+		e.level = s.Level
+		if e.gate && e.seg == e.Sustain {
+			break
+		}
+		e.start(e.seg + 1)
+	}
+	return e.level
+}
+
+func (e *Envelope) start(seg int) {
+	e.seg = seg
+	e.from = e.level
+	e.i = 0
+	e.n = 0
+	if seg < len(e.Segments) {
+		e.n = int(e.Segments[seg].Time*e.sampleRate + .5)
+	}
+}
+
+func curve(from, to, t, c float32) float32 {
+	if c == 0 {
+		return from + (to-from)*t
+	}
+	return from + (to-from)*float32(math.Expm1(-float64(c*t))/math.Expm1(-float64(c)))
+}
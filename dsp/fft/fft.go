@@ -0,0 +1,120 @@
+// Package fft computes discrete Fourier transforms of any length and provides short-time Fourier transform block processing.
+//
+// Transforms are mixed-radix Cooley-Tukey; lengths whose prime factors are small are fastest.
+// Plans hold scratch space and must not be used concurrently.
+package fft
+
+import "math"
+
+// A Plan computes complex discrete Fourier transforms of a fixed length.
+type Plan struct {
+	n        int
+	factors  []int
+	twiddles []complex64
+	in       []complex64
+	scratch  []complex64
+}
+
+// New returns a Plan for transforms of length n.
+func New(n int) *Plan {
+	if n < 1 {
+		panic("fft: length must be positive")
+	}
+	p := &Plan{
+		n:        n,
+		twiddles: make([]complex64, n),
+		in:       make([]complex64, n),
+	}
+	for i := range p.twiddles {
+		s, c := math.Sincos(-2 * math.Pi * float64(i) / float64(n))
+		p.twiddles[i] = complex(float32(c), float32(s))
+	}
+	maxRadix := 0
+	for m := n; m > 1; {
+		r := 2
+		for m%r != 0 {
+			r++
+		}
+		p.factors = append(p.factors, r)
+		m /= r
+		if r > maxRadix {
+			maxRadix = r
+		}
+	}
+	p.scratch = make([]complex64, maxRadix)
+	return p
+}
+
+// Len returns the length of the transform.
+func (p *Plan) Len() int { return p.n }
+
+// Forward sets dst to the discrete Fourier transform of src.
+// Both must have length p.Len(); they may be the same slice.
+func (p *Plan) Forward(dst, src []complex64) {
+	copy(p.in, src)
+	p.work(dst, p.in, 1, p.factors)
+}
+
+// Inverse sets dst to the inverse discrete Fourier transform of src, scaled by 1/p.Len() so that it inverts Forward.
+// Both must have length p.Len(); they may be the same slice.
+func (p *Plan) Inverse(dst, src []complex64) {
+	for i, x := range src {
+		p.in[i] = conj(x)
+	}
+	p.work(dst, p.in, 1, p.factors)
+	scale := 1 / float32(p.n)
+	for i, x := range dst {
+		dst[i] = conj(x) * complex(scale, 0)
+	}
+}
+
+// work computes the transform of the strided input in into out, recursively decimating in time by factors.
+func (p *Plan) work(out, in []complex64, stride int, factors []int) {
+	if len(factors) == 0 {
+		out[0] = in[0]
+		return
+	}
+	r := factors[0]
+	m := len(out) / r
+	for j := 0; j < r; j++ {
+		p.work(out[j*m:(j+1)*m], in[j*stride:], stride*r, factors[1:])
+	}
+	if r == 2 {
+		p.butterfly2(out, stride, m)
+	} else {
+		p.butterfly(out, stride, m, r)
+	}
+}
+
+func (p *Plan) butterfly2(out []complex64, stride, m int) {
+	a, b := out[:m], out[m:2*m]
+	for i := range a {
+		t := b[i] * p.twiddles[i*stride]
+		b[i] = a[i] - t
+		a[i] += t
+	}
+}
+
+func (p *Plan) butterfly(out []complex64, stride, m, r int) {
+	scratch := p.scratch[:r]
+	for u := 0; u < m; u++ {
+		for q, k := 0, u; q < r; q, k = q+1, k+m {
+			scratch[q] = out[k]
+		}
+		for q, k := 0, u; q < r; q, k = q+1, k+m {
+			out[k] = scratch[0]
+			tw := 0
+			for j := 1; j < r; j++ {
+				tw += stride * k
+				if tw >= p.n {
+					tw -= p.n
+				}
+				out[k] += scratch[j] * p.twiddles[tw]
+			}
+		}
+	}
+}
+
+func conj(x complex64) complex64 {
+	return complex(real(x), -imag(x))
+}
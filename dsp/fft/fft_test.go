@@ -0,0 +1,79 @@
+package fft
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+)
+
+// dft computes the discrete Fourier transform of x directly.
+func dft(x []complex128) []complex128 {
+	n := len(x)
+	y := make([]complex128, n)
+	for k := range y {
+		for j, v := range x {
+			y[k] += v * cmplx.Rect(1, -2*math.Pi*float64(j*k%n)/float64(n))
+		}
+	}
+	return y
+}
+
+var lengths = []int{1, 2, 3, 4, 5, 7, 8, 12, 15, 16, 30, 49, 64, 97, 100, 210, 256, 1000}
+
+func TestPlan(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, n := range lengths {
+		x := make([]complex64, n)
+		x128 := make([]complex128, n)
+		for i := range x {
+			x[i] = complex(float32(rng.NormFloat64()), float32(rng.NormFloat64()))
+			x128[i] = complex128(x[i])
+		}
+		p := New(n)
+		y := make([]complex64, n)
+		p.Forward(y, x)
+		want := dft(x128)
+		tol := 1e-5 * float64(n)
+		for k := range y {
+			if d := cmplx.Abs(complex128(y[k]) - want[k]); d > tol {
+				t.Fatalf("n=%d: Forward bin %d = %v, want %v", n, k, y[k], want[k])
+			}
+		}
+		p.Inverse(y, y)
+		for i := range y {
+			if d := cmplx.Abs(complex128(y[i] - x[i])); d > 1e-5 {
+				t.Fatalf("n=%d: Inverse sample %d = %v, want %v", n, i, y[i], x[i])
+			}
+		}
+	}
+}
+
+func TestRealPlan(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, n := range lengths {
+		x := make([]float32, n)
+		x128 := make([]complex128, n)
+		for i := range x {
+			x[i] = float32(rng.NormFloat64())
+			x128[i] = complex(float64(x[i]), 0)
+		}
+		p := NewReal(n)
+		y := make([]complex64, n/2+1)
+		p.Forward(y, x)
+		want := dft(x128)
+		tol := 1e-5 * float64(n)
+		for k := range y {
+			if d := cmplx.Abs(complex128(y[k]) - want[k]); d > tol {
+				t.Fatalf("n=%d: Forward bin %d = %v, want %v", n, k, y[k], want[k])
+			}
+		}
+		z := make([]float32, n)
+		p.Inverse(z, y)
+		for i := range z {
+			if d := math.Abs(float64(z[i] - x[i])); d > 1e-5 {
+				t.Fatalf("n=%d: Inverse sample %d = %v, want %v", n, i, z[i], x[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package fft
+
+import "math"
+
+// A RealPlan computes discrete Fourier transforms of real signals of a fixed length.
+// The spectrum of a real signal of length n is conjugate symmetric, so only its first n/2+1 bins are computed.
+type RealPlan struct {
+	n        int
+	half     *Plan // for even n
+	full     *Plan // for odd n
+	twiddles []complex64
+	buf      []complex64
+}
+
+// NewReal returns a RealPlan for transforms of length n.
+func NewReal(n int) *RealPlan {
+	p := &RealPlan{n: n}
+	if n%2 == 1 {
+		p.full = New(n)
+		p.buf = make([]complex64, n)
+		return p
+	}
+	p.half = New(n / 2)
+	p.buf = make([]complex64, n/2)
+	p.twiddles = make([]complex64, n/2)
+	for i := range p.twiddles {
+		s, c := math.Sincos(-2 * math.Pi * float64(i) / float64(n))
+		p.twiddles[i] = complex(float32(c), float32(s))
+	}
+	return p
+}
+
+// Len returns the length of the real signal.
+func (p *RealPlan) Len() int { return p.n }
+
+// Forward sets dst (of length p.Len()/2+1) to the first half of the discrete Fourier transform of src (of length p.Len()).
+func (p *RealPlan) Forward(dst []complex64, src []float32) {
+	if p.full != nil {
+		for i, x := range src {
+			p.buf[i] = complex(x, 0)
+		}
+		p.full.Forward(p.buf, p.buf)
+		copy(dst, p.buf)
+		return
+	}
+
+	// Transform the even and odd samples together as the real and imaginary parts of a half-length signal, then separate them.
+	h := p.n / 2
+	for i := range p.buf {
+		p.buf[i] = complex(src[2*i], src[2*i+1])
+	}
+	p.half.Forward(p.buf, p.buf)
+	z0 := p.buf[0]
+	dst[0] = complex(real(z0)+imag(z0), 0)
+	dst[h] = complex(real(z0)-imag(z0), 0)
+	for k := 1; k < h; k++ {
+		a, b := p.buf[k], conj(p.buf[h-k])
+		even := (a + b) / 2
+		odd := (a - b) * complex(0, -.5)
+		dst[k] = even + p.twiddles[k]*odd
+	}
+}
+
+// Inverse sets dst (of length p.Len()) to the real inverse discrete Fourier transform of the half spectrum src
+// (of length p.Len()/2+1), scaled by 1/p.Len() so that it inverts Forward.
+func (p *RealPlan) Inverse(dst []float32, src []complex64) {
+	if p.full != nil {
+		copy(p.buf, src)
+		for k := len(src); k < p.n; k++ {
+			p.buf[k] = conj(src[p.n-k])
+		}
+		p.full.Inverse(p.buf, p.buf)
+		for i, x := range p.buf {
+			dst[i] = real(x)
+		}
+		return
+	}
+
+	h := p.n / 2
+	for k := 0; k < h; k++ {
+		a, b := src[k], conj(src[h-k])
+		even := (a + b) / 2
+		odd := (a - b) / 2 * conj(p.twiddles[k])
+		p.buf[k] = even + complex(0, 1)*odd
+	}
+	p.half.Inverse(p.buf, p.buf)
+	for i, z := range p.buf {
+		dst[2*i] = real(z)
+		dst[2*i+1] = imag(z)
+	}
+}
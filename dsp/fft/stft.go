@@ -0,0 +1,94 @@
+package fft
+
+import "math"
+
+// An STFT processes a signal one sample at a time by short-time Fourier transform and weighted overlap-add.
+// Every hop samples, the last size samples are windowed and transformed, the spectrum is passed to a callback for modification,
+// and the result is transformed back, windowed again and added to the output.
+// The analysis and synthesis windows are both the square root of a periodic Hann window, so an unmodified spectrum is reconstructed exactly
+// (after a latency of size samples) when hop divides size/2.
+type STFT struct {
+	size, hop int
+	process   func(spectrum []complex64)
+	plan      *RealPlan
+	window    []float32
+	in, out   []float32
+	frame     []float32
+	spectrum  []complex64
+	pos       int
+}
+
+// NewSTFT returns an STFT with frames of size samples spaced hop samples apart, which calls process with the spectrum
+// (of length size/2+1) of each frame.
+// Hop must be between 1 and size.
+func NewSTFT(size, hop int, process func(spectrum []complex64)) *STFT {
+	if hop < 1 || hop > size {
+		panic("fft: hop must be between 1 and the frame size")
+	}
+	s := &STFT{
+		size:     size,
+		hop:      hop,
+		process:  process,
+		plan:     NewReal(size),
+		window:   make([]float32, size),
+		in:       make([]float32, size),
+		out:      make([]float32, size),
+		frame:    make([]float32, size),
+		spectrum: make([]complex64, size/2+1),
+	}
+	for i := range s.window {
+		s.window[i] = float32(math.Sqrt(.5 - .5*math.Cos(2*math.Pi*float64(i)/float64(size))))
+	}
+	// Normalize so that the overlapping products of the windows sum to 1.
+	sum := float32(0)
+	for i := 0; i < size; i += hop {
+		sum += s.window[i] * s.window[i]
+	}
+	scale := float32(math.Sqrt(float64(1 / sum)))
+	for i := range s.window {
+		s.window[i] *= scale
+	}
+	return s
+}
+
+// Size returns the frame size.
+func (s *STFT) Size() int { return s.size }
+
+// Hop returns the number of samples between frames.
+func (s *STFT) Hop() int { return s.hop }
+
+// Latency returns the delay in samples between input and output.
+func (s *STFT) Latency() int { return s.size }
+
+// Window returns the analysis window, which includes the overlap-add normalization.
+func (s *STFT) Window() []float32 { return s.window }
+
+// Process adds x to the input and returns the next output sample.
+func (s *STFT) Process(x float32) float32 {
+	s.in[s.size-s.hop+s.pos] = x
+	y := s.out[s.pos]
+	s.pos++
+	if s.pos == s.hop {
+		s.pos = 0
+		s.processFrame()
+	}
+	return y
+}
+
+func (s *STFT) processFrame() {
+	for i, x := range s.in {
+		s.frame[i] = x * s.window[i]
+	}
+	s.plan.Forward(s.spectrum, s.frame)
+	s.process(s.spectrum)
+	s.plan.Inverse(s.frame, s.spectrum)
+
+	copy(s.out, s.out[s.hop:])
+	for i := s.size - s.hop; i < s.size; i++ {
+		s.out[i] = 0
+	}
+	for i, x := range s.frame {
+		s.out[i] += x * s.window[i]
+	}
+	copy(s.in, s.in[s.hop:])
+}
@@ -0,0 +1,67 @@
+package dsp
+
+import "github.com/gordonklaus/dsp/dsp/fir"
+
+// FIR convolves x with Kernel.
+// Kernel must be set before Init; package github.com/gordonklaus/dsp/dsp/fir designs kernels.
+// Without a kernel, x is passed through unchanged.
+type FIR struct {
+	Kernel []float32
+
+	x   []float32
+	pos int
+}
+
+func (f *FIR) Init(c Config) {
+	f.x = make([]float32, 2*len(f.Kernel))
+	f.pos = 0
+}
+
+func (f *FIR) Process(x float32) float32 {
+	n := len(f.Kernel)
+	if n == 0 {
+		return x
+	}
+	// Each sample is stored twice so that the last n samples are contiguous, newest first.
+	f.pos--
+	if f.pos < 0 {
+		f.pos = n - 1
+	}
+	f.x[f.pos] = x
+	f.x[f.pos+n] = x
+	y := float32(0)
+	for i, x := range f.x[f.pos : f.pos+n] {
+		y += f.Kernel[i] * x
+	}
+	return y
+}
+
+// FIRLowPass is a linear-phase low-pass filter with its cutoff at cutoff Hz: a 63-tap Blackman-windowed sinc FIR filter,
+// which is redesigned whenever cutoff changes.
+// Its output is delayed by Latency samples.
+type FIRLowPass struct {
+	FIR
+	sampleRate float32
+	cutoff     float32
+}
+
+const firLowPassTaps = 63
+
+func (f *FIRLowPass) Init(c Config) {
+	f.sampleRate = c.SampleRate
+	f.cutoff = -1
+	f.Kernel = make([]float32, firLowPassTaps)
+	f.FIR.Init(c)
+}
+
+// Latency returns the delay in samples between input and output.
+func (f *FIRLowPass) Latency() int { return firLowPassTaps / 2 }
+
+func (f *FIRLowPass) Process(x, cutoff float32) float32 {
+	if cutoff != f.cutoff {
+		f.cutoff = cutoff
+		freq := clamp(float64(cutoff/f.sampleRate), 1e-4, .5)
+		copy(f.Kernel, fir.LowPass(firLowPassTaps, freq, fir.Blackman))
+	}
+	return f.FIR.Process(x)
+}
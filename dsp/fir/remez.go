@@ -0,0 +1,206 @@
+package fir
+
+import (
+	"errors"
+	"math"
+)
+
+// Remez returns an n-tap linear-phase kernel designed with the Parks-McClellan algorithm,
+// which minimizes the maximum weighted deviation from a piecewise-constant desired response.
+// Bands holds pairs of band edges in increasing order; desired and weights hold the gain and error weight of each band.
+// Frequencies between bands are don't-care transition regions.
+func Remez(n int, bands, desired, weights []float64) ([]float32, error) {
+	if n < 3 {
+		return nil, errors.New("fir: Remez requires at least 3 taps")
+	}
+	if len(bands) == 0 || len(bands)%2 != 0 || len(desired) != len(bands)/2 || len(weights) != len(bands)/2 {
+		return nil, errors.New("fir: Remez requires a pair of band edges and one desired gain and weight per band")
+	}
+	for i, f := range bands {
+		if f < 0 || f > .5 || i > 0 && f < bands[i-1] {
+			return nil, errors.New("fir: Remez band edges must be increasing and between 0 and 0.5")
+		}
+	}
+	for _, w := range weights {
+		if w <= 0 {
+			return nil, errors.New("fir: Remez weights must be positive")
+		}
+	}
+
+	// An odd-length kernel has r cosine terms.
+	// An even-length kernel has a factor of cos(πf) and r cosine terms in the remainder, which is designed instead.
+	odd := n%2 == 1
+	r := n / 2
+	if odd {
+		r++
+	}
+
+	const density = 16
+	step := .5 / float64(density*r)
+	var gx, gd, gw []float64
+	for b := 0; b < len(bands)/2; b++ {
+		lo, hi := bands[2*b], bands[2*b+1]
+		if !odd && hi > .5-step {
+			hi = .5 - step
+		}
+		if lo > hi {
+			continue
+		}
+		k := int(math.Ceil((hi - lo) / step))
+		if k < 1 {
+			k = 1
+		}
+		for i := 0; i <= k; i++ {
+			f := lo + (hi-lo)*float64(i)/float64(k)
+			d, w := desired[b], weights[b]
+			if !odd {
+				c := math.Cos(math.Pi * f)
+				d /= c
+				w *= c
+			}
+			gx = append(gx, math.Cos(2*math.Pi*f))
+			gd = append(gd, d)
+			gw = append(gw, w)
+		}
+	}
+	if len(gx) < r+1 {
+		return nil, errors.New("fir: Remez bands are too narrow")
+	}
+
+	ext := make([]int, r+1)
+	for i := range ext {
+		ext[i] = i * (len(gx) - 1) / r
+	}
+	x := make([]float64, r+1)
+	c := make([]float64, r)
+	e := make([]float64, len(gx))
+	var bw []float64
+	const maxIterations = 40
+	for iter := 0; ; iter++ {
+		if iter == maxIterations {
+			return nil, errors.New("fir: Remez did not converge")
+		}
+		for k, i := range ext {
+			x[k] = gx[i]
+		}
+		ad := baryWeights(x)
+		var num, den float64
+		sign := 1.
+		for k, i := range ext {
+			num += ad[k] * gd[i]
+			den += sign * ad[k] / gw[i]
+			sign = -sign
+		}
+		dev := num / den
+		sign = 1
+		for k, i := range ext[:r] {
+			c[k] = gd[i] - sign*dev/gw[i]
+			sign = -sign
+		}
+		bw = baryWeights(x[:r])
+
+		maxErr := 0.
+		for i := range gx {
+			e[i] = gw[i] * (gd[i] - baryInterp(gx[i], x[:r], bw, c))
+			maxErr = math.Max(maxErr, math.Abs(e[i]))
+		}
+		next := extremals(e, r+1)
+		if next == nil {
+			return nil, errors.New("fir: Remez found too few extremal frequencies")
+		}
+		if equalInts(next, ext) || maxErr-math.Abs(dev) <= 1e-6*maxErr {
+			break
+		}
+		ext = next
+	}
+
+	hr := make([]float64, n)
+	for k := range hr {
+		f := float64(k) / float64(n)
+		hr[k] = baryInterp(math.Cos(2*math.Pi*f), x[:r], bw, c)
+		if !odd {
+			hr[k] *= math.Cos(math.Pi * f)
+		}
+	}
+	h := make([]float32, n)
+	m := float64(n-1) / 2
+	for i := range h {
+		s := 0.
+		for k, a := range hr {
+			s += a * math.Cos(2*math.Pi*float64(k)/float64(n)*(float64(i)-m))
+		}
+		h[i] = float32(s / float64(n))
+	}
+	return h, nil
+}
+
+// baryWeights returns the barycentric interpolation weights for the points x.
+// The differences are doubled to keep the products within floating point range; the common factor cancels.
+func baryWeights(x []float64) []float64 {
+	w := make([]float64, len(x))
+	for k := range x {
+		p := 1.
+		for j := range x {
+			if j != k {
+				p *= 2 * (x[k] - x[j])
+			}
+		}
+		w[k] = 1 / p
+	}
+	return w
+}
+
+func baryInterp(v float64, x, w, c []float64) float64 {
+	var num, den float64
+	for k := range x {
+		d := v - x[k]
+		if math.Abs(d) < 1e-14 {
+			return c[k]
+		}
+		t := w[k] / d
+		num += t * c[k]
+		den += t
+	}
+	return num / den
+}
+
+// extremals returns the indices of m alternating local extrema of e, or nil if there are too few.
+func extremals(e []float64, m int) []int {
+	var ext []int
+	for i := range e {
+		if i > 0 && e[i] > 0 && e[i] < e[i-1] || i < len(e)-1 && e[i] > 0 && e[i] < e[i+1] ||
+			i > 0 && e[i] <= 0 && e[i] > e[i-1] || i < len(e)-1 && e[i] <= 0 && e[i] > e[i+1] {
+			continue
+		}
+		if j := len(ext) - 1; j >= 0 && (e[i] > 0) == (e[ext[j]] > 0) {
+			if math.Abs(e[i]) > math.Abs(e[ext[j]]) {
+				ext[j] = i
+			}
+			continue
+		}
+		ext = append(ext, i)
+	}
+	if len(ext) < m {
+		return nil
+	}
+	for len(ext) > m {
+		if math.Abs(e[ext[0]]) < math.Abs(e[ext[len(ext)-1]]) {
+			ext = ext[1:]
+		} else {
+			ext = ext[:len(ext)-1]
+		}
+	}
+	return ext
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+// Package fir designs finite impulse response filter kernels for use with dsp.FIR.
+// Frequencies are given as fractions of the sample rate (0..0.5).
+package fir
+
+import "math"
+
+// LowPass returns an n-tap windowed-sinc low-pass kernel with unity gain at DC.
+func LowPass(n int, cutoff float64, w Window) []float32 {
+	h := sinc(n, cutoff, w)
+	return normalize(h, 0)
+}
+
+// HighPass returns an n-tap windowed-sinc high-pass kernel with unity gain at the Nyquist frequency.
+// n must be odd.
+func HighPass(n int, cutoff float64, w Window) []float32 {
+	if n%2 == 0 {
+		panic("fir: HighPass requires an odd number of taps")
+	}
+	h := sinc(n, .5-cutoff, w)
+	for i := range h {
+		if i%2 == 1 {
+			h[i] = -h[i]
+		}
+	}
+	return normalize(h, .5)
+}
+
+// BandPass returns an n-tap windowed-sinc band-pass kernel with unity gain at the center of the passband.
+func BandPass(n int, low, high float64, w Window) []float32 {
+	h := sinc(n, (high-low)/2, w)
+	center := (low + high) / 2
+	m := float64(n-1) / 2
+	for i := range h {
+		h[i] *= 2 * math.Cos(2*math.Pi*center*(float64(i)-m))
+	}
+	return normalize(h, center)
+}
+
+// sinc returns an n-tap windowed-sinc low-pass kernel.
+func sinc(n int, cutoff float64, w Window) []float64 {
+	h := make([]float64, n)
+	m := float64(n-1) / 2
+	for i := range h {
+		x := float64(i) - m
+		if x == 0 {
+			h[i] = 2 * cutoff
+		} else {
+			h[i] = math.Sin(2*math.Pi*cutoff*x) / (math.Pi * x)
+		}
+		h[i] *= w(i, n)
+	}
+	return h
+}
+
+// normalize scales h to unity gain at freq and converts it to float32.
+func normalize(h []float64, freq float64) []float32 {
+	g := gain(h, freq)
+	k := make([]float32, len(h))
+	for i := range h {
+		k[i] = float32(h[i] / g)
+	}
+	return k
+}
+
+// gain returns the magnitude of the frequency response of h at freq.
+func gain(h []float64, freq float64) float64 {
+	var re, im float64
+	for i, x := range h {
+		s, c := math.Sincos(2 * math.Pi * freq * float64(i))
+		re += x * c
+		im -= x * s
+	}
+	return math.Hypot(re, im)
+}
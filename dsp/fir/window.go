@@ -0,0 +1,67 @@
+package fir
+
+import "math"
+
+// A Window returns the weight of sample i of an n-sample window.
+type Window func(i, n int) float64
+
+// Rectangular is the rectangular (boxcar) window, i.e. no windowing.
+func Rectangular(i, n int) float64 {
+	return 1
+}
+
+// Hann is the Hann (raised cosine) window.
+func Hann(i, n int) float64 {
+	if n == 1 {
+		return 1
+	}
+	return .5 - .5*math.Cos(2*math.Pi*float64(i)/float64(n-1))
+}
+
+// Blackman is the Blackman window.
+func Blackman(i, n int) float64 {
+	if n == 1 {
+		return 1
+	}
+	x := 2 * math.Pi * float64(i) / float64(n-1)
+	return .42 - .5*math.Cos(x) + .08*math.Cos(2*x)
+}
+
+// Kaiser returns the Kaiser window with shape parameter beta.
+func Kaiser(beta float64) Window {
+	return func(i, n int) float64 {
+		if n == 1 {
+			return 1
+		}
+		r := 2*float64(i)/float64(n-1) - 1
+		return bessel0(beta*math.Sqrt(1-r*r)) / bessel0(beta)
+	}
+}
+
+// KaiserBeta returns the Kaiser window shape parameter that gives a stopband attenuation of atten decibels.
+func KaiserBeta(atten float64) float64 {
+	switch {
+	case atten > 50:
+		return .1102 * (atten - 8.7)
+	case atten >= 21:
+		return .5842*math.Pow(atten-21, .4) + .07886*(atten-21)
+	}
+	return 0
+}
+
+// KaiserLength returns the number of taps needed by a Kaiser-windowed filter with a stopband attenuation of atten decibels
+// and a transition width of width (as a fraction of the sample rate).
+func KaiserLength(atten, width float64) int {
+	return int(math.Ceil((atten-7.95)/(14.36*width))) + 1
+}
+
+// bessel0 is the zeroth-order modified Bessel function of the first kind.
+func bessel0(x float64) float64 {
+	sum, term := 1., 1.
+	for k := 1; term > 1e-12*sum; k++ {
+		t := x / (2 * float64(k))
+		term *= t * t
+		sum += term
+	}
+	return sum
+}
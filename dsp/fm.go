@@ -0,0 +1,160 @@
+package dsp
+
+import "math"
+
+// Operator is a phase modulation (FM) operator: a sine oscillator at freq times ratio whose phase is offset by phase (in cycles),
+// typically the output of another operator, and by its own output scaled by feedback (0..1).
+// Level scales the output.
+type Operator struct {
+	phasor
+	y1, y2 float32
+}
+
+// fmFeedback is the phase offset in cycles due to feedback from an operator with output 1 at full feedback.
+const fmFeedback = .5
+
+func (o *Operator) Init(c Config) {
+	o.phasor.init(c)
+	o.y1, o.y2 = 0, 0
+}
+
+func (o *Operator) Process(freq, phase, ratio, feedback, level float32) float32 {
+	t, _ := o.next(freq * ratio)
+	// Feedback from the average of the last two outputs does not oscillate at high feedback, as it would from the last output alone.
+	phase += feedback * fmFeedback * (o.y1 + o.y2) / 2
+	y := level * float32(math.Sin(2*math.Pi*float64(t+phase)))
+	o.y1, o.y2 = y, o.y1
+	return y
+}
+
+// FM4 is a four-operator FM synthesizer voice with the eight algorithms of the Yamaha DX21 and TX81Z,
+// in which operator 4 has feedback.
+// Algorithm (1..8) selects how the operators modulate each other; those that do not modulate another are carriers, which are mixed to the output.
+// Each operator runs at freq times its ratio input (0 is taken to be 1) and its output is scaled by its level input.
+// A modulator at level 1 offsets the phase of the operators it modulates by up to one cycle.
+type FM4 struct {
+	fm
+}
+
+func (f *FM4) Init(c Config) {
+	f.fm.init(c, 4)
+}
+
+func (f *FM4) Process(freq, algorithm, feedback, ratio1, ratio2, ratio3, ratio4, level1, level2, level3, level4 float32) float32 {
+	ratios := [...]float32{ratio1, ratio2, ratio3, ratio4}
+	levels := [...]float32{level1, level2, level3, level4}
+	return f.process(freq, ratios[:], levels[:], fm4Algorithms, algorithm, feedback)
+}
+
+// FM6 is a six-operator FM synthesizer voice with the 32 algorithms of the Yamaha DX7.
+// It is otherwise like FM4.
+type FM6 struct {
+	fm
+}
+
+func (f *FM6) Init(c Config) {
+	f.fm.init(c, 6)
+}
+
+func (f *FM6) Process(freq, algorithm, feedback, ratio1, ratio2, ratio3, ratio4, ratio5, ratio6, level1, level2, level3, level4, level5, level6 float32) float32 {
+	ratios := [...]float32{ratio1, ratio2, ratio3, ratio4, ratio5, ratio6}
+	levels := [...]float32{level1, level2, level3, level4, level5, level6}
+	return f.process(freq, ratios[:], levels[:], fm6Algorithms, algorithm, feedback)
+}
+
+// fm implements a bank of operators connected by an algorithm.
+type fm struct {
+	ops      []phasor
+	out      []float32
+	fb1, fb2 float32 // the last two outputs of the operator with feedback
+}
+
+func (f *fm) init(c Config, n int) {
+	*f = fm{ops: make([]phasor, n), out: make([]float32, n)}
+	for i := range f.ops {
+		f.ops[i].init(c)
+	}
+}
+
+// process computes the operators from the highest numbered to the lowest, which is the order of modulation in every algorithm.
+func (f *fm) process(freq float32, ratios, levels []float32, algorithms []fmAlgorithm, algorithm, feedback float32) float32 {
+	a := &algorithms[int(clamp(float64(round(algorithm)), 1, float64(len(algorithms))))-1]
+	for i := len(f.ops) - 1; i >= 0; i-- {
+		op := i + 1
+		phase := float32(0)
+		for _, m := range a.modulators {
+			if m[1] == op {
+				phase += f.out[m[0]-1]
+			}
+		}
+		if a.feedback[1] == op {
+			phase += feedback * fmFeedback * (f.fb1 + f.fb2) / 2
+		}
+		ratio := ratios[i]
+		if ratio == 0 {
+			ratio = 1
+		}
+		t, _ := f.ops[i].next(freq * ratio)
+		f.out[i] = levels[i] * float32(math.Sin(2*math.Pi*float64(t+phase)))
+	}
+	f.fb1, f.fb2 = f.out[a.feedback[0]-1], f.fb1
+
+	y := float32(0)
+	for _, op := range a.carriers {
+		y += f.out[op-1]
+	}
+	return y / float32(len(a.carriers))
+}
+
+// An fmAlgorithm connects the operators of an FM voice, which are numbered from 1.
+type fmAlgorithm struct {
+	carriers   []int
+	modulators [][2]int // pairs of modulating and modulated operators
+	feedback   [2]int   // the operator whose output is fed back and the operator whose phase it offsets
+}
+
+var fm4Algorithms = []fmAlgorithm{
+	{[]int{1}, [][2]int{{4, 3}, {3, 2}, {2, 1}}, [2]int{4, 4}},
+	{[]int{1}, [][2]int{{4, 2}, {3, 2}, {2, 1}}, [2]int{4, 4}},
+	{[]int{1}, [][2]int{{4, 1}, {3, 2}, {2, 1}}, [2]int{4, 4}},
+	{[]int{1}, [][2]int{{4, 3}, {3, 1}, {2, 1}}, [2]int{4, 4}},
+	{[]int{1, 3}, [][2]int{{4, 3}, {2, 1}}, [2]int{4, 4}},
+	{[]int{1, 2, 3}, [][2]int{{4, 3}, {4, 2}, {4, 1}}, [2]int{4, 4}},
+	{[]int{1, 2, 3}, [][2]int{{4, 3}}, [2]int{4, 4}},
+	{[]int{1, 2, 3, 4}, nil, [2]int{4, 4}},
+}
+
+var fm6Algorithms = []fmAlgorithm{
+	{[]int{1, 3}, [][2]int{{2, 1}, {6, 5}, {5, 4}, {4, 3}}, [2]int{6, 6}},
+	{[]int{1, 3}, [][2]int{{2, 1}, {6, 5}, {5, 4}, {4, 3}}, [2]int{2, 2}},
+	{[]int{1, 4}, [][2]int{{3, 2}, {2, 1}, {6, 5}, {5, 4}}, [2]int{6, 6}},
+	{[]int{1, 4}, [][2]int{{3, 2}, {2, 1}, {6, 5}, {5, 4}}, [2]int{4, 6}},
+	{[]int{1, 3, 5}, [][2]int{{2, 1}, {4, 3}, {6, 5}}, [2]int{6, 6}},
+	{[]int{1, 3, 5}, [][2]int{{2, 1}, {4, 3}, {6, 5}}, [2]int{5, 6}},
+	{[]int{1, 3}, [][2]int{{2, 1}, {4, 3}, {6, 5}, {5, 3}}, [2]int{6, 6}},
+	{[]int{1, 3}, [][2]int{{2, 1}, {4, 3}, {6, 5}, {5, 3}}, [2]int{4, 4}},
+	{[]int{1, 3}, [][2]int{{2, 1}, {4, 3}, {6, 5}, {5, 3}}, [2]int{2, 2}},
+	{[]int{1, 4}, [][2]int{{3, 2}, {2, 1}, {5, 4}, {6, 4}}, [2]int{3, 3}},
+	{[]int{1, 4}, [][2]int{{3, 2}, {2, 1}, {5, 4}, {6, 4}}, [2]int{6, 6}},
+	{[]int{1, 3}, [][2]int{{2, 1}, {4, 3}, {5, 3}, {6, 3}}, [2]int{2, 2}},
+	{[]int{1, 3}, [][2]int{{2, 1}, {4, 3}, {5, 3}, {6, 3}}, [2]int{6, 6}},
+	{[]int{1, 3}, [][2]int{{2, 1}, {4, 3}, {5, 4}, {6, 4}}, [2]int{6, 6}},
+	{[]int{1, 3}, [][2]int{{2, 1}, {4, 3}, {5, 4}, {6, 4}}, [2]int{2, 2}},
+	{[]int{1}, [][2]int{{2, 1}, {4, 3}, {3, 1}, {6, 5}, {5, 1}}, [2]int{6, 6}},
+	{[]int{1}, [][2]int{{2, 1}, {4, 3}, {3, 1}, {6, 5}, {5, 1}}, [2]int{2, 2}},
+	{[]int{1}, [][2]int{{2, 1}, {3, 1}, {6, 5}, {5, 4}, {4, 1}}, [2]int{3, 3}},
+	{[]int{1, 4, 5}, [][2]int{{3, 2}, {2, 1}, {6, 4}, {6, 5}}, [2]int{6, 6}},
+	{[]int{1, 2, 4}, [][2]int{{3, 1}, {3, 2}, {5, 4}, {6, 4}}, [2]int{3, 3}},
+	{[]int{1, 2, 4, 5}, [][2]int{{3, 1}, {3, 2}, {6, 4}, {6, 5}}, [2]int{3, 3}},
+	{[]int{1, 3, 4, 5}, [][2]int{{2, 1}, {6, 3}, {6, 4}, {6, 5}}, [2]int{6, 6}},
+	{[]int{1, 2, 4, 5}, [][2]int{{3, 2}, {6, 4}, {6, 5}}, [2]int{6, 6}},
+	{[]int{1, 2, 3, 4, 5}, [][2]int{{6, 3}, {6, 4}, {6, 5}}, [2]int{6, 6}},
+	{[]int{1, 2, 3, 4, 5}, [][2]int{{6, 4}, {6, 5}}, [2]int{6, 6}},
+	{[]int{1, 2, 4}, [][2]int{{3, 2}, {5, 4}, {6, 4}}, [2]int{6, 6}},
+	{[]int{1, 2, 4}, [][2]int{{3, 2}, {5, 4}, {6, 4}}, [2]int{3, 3}},
+	{[]int{1, 3, 6}, [][2]int{{2, 1}, {5, 4}, {4, 3}}, [2]int{5, 5}},
+	{[]int{1, 2, 3, 5}, [][2]int{{4, 3}, {6, 5}}, [2]int{6, 6}},
+	{[]int{1, 2, 3, 6}, [][2]int{{5, 4}, {4, 3}}, [2]int{5, 5}},
+	{[]int{1, 2, 3, 4, 5}, [][2]int{{6, 5}}, [2]int{6, 6}},
+	{[]int{1, 2, 3, 4, 5, 6}, nil, [2]int{6, 6}},
+}
@@ -0,0 +1,158 @@
+package dsp
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Granular is a granular synthesizer that plays many short, overlapping, Hann-windowed grains of the recent past of x.
+// Size is the length of each grain in seconds and density the number of grains started per second.
+// Position is how far back in seconds (up to granularMaxPosition) each grain starts reading;
+// pitch is the playback rate of each grain (1 is the original pitch, 2 an octave up).
+// Jitter (0..1) randomizes the time between grains and the position of each grain, by up to that fraction of the interval and the grain size.
+type Granular struct {
+	rand       *rand.Rand
+	sampleRate float32
+	buf        ring
+	grains     [granularMaxGrains]grain
+	next       float64 // samples until the next grain starts
+}
+
+// grain is one of the grains of a Granular.
+type grain struct {
+	delay  float64 // how far behind the newest input the grain is reading, in samples
+	rate   float64
+	t, dt  float64 // progress through the grain's window (0..1) and its increment per sample
+	active bool
+}
+
+const (
+	granularMaxGrains   = 64
+	granularMaxPosition = 4
+	granularMaxSize     = 1
+)
+
+func (g *Granular) Init(c Config) {
+	*g = Granular{rand: c.GetRand(), sampleRate: c.SampleRate}
+	// A grain read at the highest pitch runs ahead of the input by up to twice its length.
+	g.buf.init(int(c.SampleRate*(granularMaxPosition+2*granularMaxSize)) + 4)
+}
+
+func (g *Granular) Process(x, size, density, position, pitch, jitter float32) float32 {
+	g.buf.write(x)
+	size = float32(clamp(float64(size), .001, granularMaxSize))
+	jitter = float32(clamp(float64(jitter), 0, 1))
+
+	if g.next--; g.next <= 0 {
+		interval := float64(g.sampleRate)
+		if density > 0 {
+			interval /= float64(density)
+		}
+		g.next = math.Max(1, interval*(1+float64(jitter)*(2*g.rand.Float64()-1)))
+		if density > 0 {
+			g.start(size, position, pitch, jitter)
+		}
+	}
+
+	y := float32(0)
+	for i := range g.grains {
+		gr := &g.grains[i]
+		if !gr.active {
+			continue
+		}
+		w := math.Sin(math.Pi * gr.t)
+		y += float32(w*w) * g.buf.read(gr.delay)
+		gr.delay += 1 - gr.rate
+		if gr.t += gr.dt; gr.t >= 1 {
+			gr.active = false
+		}
+	}
+	// Hann windows overlapping by half sum to 1; more overlap is scaled down to match.
+	if overlap := density * size; overlap > 2 {
+		y *= 2 / overlap
+	}
+	return y
+}
+
+// start starts a grain, unless all are already playing.
+func (g *Granular) start(size, position, pitch, jitter float32) {
+	for i := range g.grains {
+		gr := &g.grains[i]
+		if gr.active {
+			continue
+		}
+		n := float64(size * g.sampleRate)
+		rate := clamp(float64(pitch), 0, 2)
+		delay := float64(position*g.sampleRate) + n*float64(jitter)*(2*g.rand.Float64()-1)
+		// The grain must not read past the newest input, nor further back than the buffer holds.
+		delay = clamp(delay, math.Max(2, n*(rate-1)+2), float64(granularMaxPosition*g.sampleRate))
+		*gr = grain{delay: delay, rate: rate, dt: 1 / n, active: true}
+		return
+	}
+}
+
+// PitchShifter shifts the pitch of x by the ratio pitch (2 is an octave up) without changing its duration.
+// It reads from a delay line with two heads whose delays sweep over window seconds (up to pitchShifterMaxWindow),
+// crossfading to each as the other jumps back.
+// Larger windows are smoother for sustained sounds; smaller ones blur transients less.
+type PitchShifter struct {
+	sampleRate float32
+	buf        ring
+	phase      float64
+}
+
+const pitchShifterMaxWindow = .2
+
+func (p *PitchShifter) Init(c Config) {
+	*p = PitchShifter{sampleRate: c.SampleRate}
+	p.buf.init(int(c.SampleRate*pitchShifterMaxWindow) + 4)
+}
+
+func (p *PitchShifter) Process(x, pitch, window float32) float32 {
+	p.buf.write(x)
+	n := float64(float32(clamp(float64(window), .005, pitchShifterMaxWindow)) * p.sampleRate)
+	p.phase += (1 - clamp(float64(pitch), 0, 4)) / n
+	p.phase -= math.Floor(p.phase)
+
+	y := float32(0)
+	for _, t := range [2]float64{p.phase, math.Mod(p.phase+.5, 1)} {
+		w := math.Sin(math.Pi * t)
+		y += float32(w*w) * p.buf.read(1+t*(n-1))
+	}
+	return y
+}
+
+// ring is a fixed-size circular buffer of recent input.
+type ring struct {
+	x []float32
+	i int
+}
+
+func (r *ring) init(n int) {
+	*r = ring{x: make([]float32, n)}
+}
+
+func (r *ring) write(x float32) {
+	r.i++
+	if r.i == len(r.x) {
+		r.i = 0
+	}
+	r.x[r.i] = x
+}
+
+// read interpolates the input delay samples ago, which must be less than the size of the buffer less 2.
+func (r *ring) read(delay float64) float32 {
+	i, f := math.Modf(delay)
+	j := int(i)
+	at := func(k int) float32 {
+		if k < 0 {
+			k = 0
+		}
+		k = r.i - k
+		if k < 0 {
+			k += len(r.x)
+		}
+		return r.x[k]
+	}
+	return interp3(float32(f), at(j-1), at(j), at(j+1), at(j+2))
+}
@@ -0,0 +1,175 @@
+package dsp
+
+import (
+	"math"
+
+	"github.com/gordonklaus/dsp/dsp/iir"
+)
+
+// Butterworth is a Butterworth low-pass (or, if HighPass is set, high-pass) filter of any order, with its cutoff at cutoff Hz.
+// The fields must be set before Init; Order 0 is taken to be 4.
+type Butterworth struct {
+	Order    int
+	HighPass bool
+
+	iirFilter
+}
+
+func (f *Butterworth) Init(c Config) {
+	f.init(c, butterworth, f.Order, f.HighPass)
+}
+
+func (f *Butterworth) Process(x, cutoff float32) float32 {
+	f.update(cutoff, 0, 0)
+	return f.process(x)
+}
+
+// Chebyshev1 is a Chebyshev type I low-pass (or, if HighPass is set, high-pass) filter of any order, with its cutoff at cutoff Hz
+// and ripple decibels of passband ripple (0 is taken to be 1).
+// The fields must be set before Init; Order 0 is taken to be 4.
+type Chebyshev1 struct {
+	Order    int
+	HighPass bool
+
+	iirFilter
+}
+
+func (f *Chebyshev1) Init(c Config) {
+	f.init(c, chebyshev1, f.Order, f.HighPass)
+}
+
+func (f *Chebyshev1) Process(x, cutoff, ripple float32) float32 {
+	f.update(cutoff, ripple, 0)
+	return f.process(x)
+}
+
+// Chebyshev2 is a Chebyshev type II low-pass (or, if HighPass is set, high-pass) filter of any order,
+// with the edge of its stopband at cutoff Hz and attenuation decibels of stopband attenuation (0 is taken to be 60).
+// The fields must be set before Init; Order 0 is taken to be 4.
+type Chebyshev2 struct {
+	Order    int
+	HighPass bool
+
+	iirFilter
+}
+
+func (f *Chebyshev2) Init(c Config) {
+	f.init(c, chebyshev2, f.Order, f.HighPass)
+}
+
+func (f *Chebyshev2) Process(x, cutoff, attenuation float32) float32 {
+	f.update(cutoff, 0, attenuation)
+	return f.process(x)
+}
+
+// Elliptic is an elliptic (Cauer) low-pass (or, if HighPass is set, high-pass) filter of any order, with its cutoff at cutoff Hz,
+// ripple decibels of passband ripple (0 is taken to be 1) and attenuation decibels of stopband attenuation (0 is taken to be 60).
+// The fields must be set before Init; Order 0 is taken to be 4.
+type Elliptic struct {
+	Order    int
+	HighPass bool
+
+	iirFilter
+}
+
+func (f *Elliptic) Init(c Config) {
+	f.init(c, elliptic, f.Order, f.HighPass)
+}
+
+func (f *Elliptic) Process(x, cutoff, ripple, attenuation float32) float32 {
+	f.update(cutoff, ripple, attenuation)
+	return f.process(x)
+}
+
+type iirType int
+
+const (
+	butterworth iirType = iota
+	chebyshev1
+	chebyshev2
+	elliptic
+)
+
+// iirFilter is a cascade designed from an analog prototype, which is redesigned when its parameters change.
+type iirFilter struct {
+	cascade
+	sampleRate                  float32
+	typ                         iirType
+	order                       int
+	highPass                    bool
+	cutoff, ripple, attenuation float32
+	designed                    bool
+}
+
+func (f *iirFilter) init(c Config, typ iirType, order int, highPass bool) {
+	if order <= 0 {
+		order = 4
+	}
+	*f = iirFilter{sampleRate: c.SampleRate, typ: typ, order: order, highPass: highPass}
+}
+
+// update redesigns the filter if the parameters have changed, keeping its state.
+func (f *iirFilter) update(cutoff, ripple, attenuation float32) {
+	if f.designed && cutoff == f.cutoff && ripple == f.ripple && attenuation == f.attenuation {
+		return
+	}
+	f.designed = true
+	f.cutoff, f.ripple, f.attenuation = cutoff, ripple, attenuation
+
+	r, a := float64(ripple), float64(attenuation)
+	if r <= 0 {
+		r = 1
+	}
+	if a <= 0 {
+		a = 60
+	}
+	var p iir.ZPK
+	switch f.typ {
+	case butterworth:
+		p = iir.Butterworth(f.order)
+	case chebyshev1:
+		p = iir.Chebyshev1(f.order, r)
+	case chebyshev2:
+		p = iir.Chebyshev2(f.order, a)
+	case elliptic:
+		p = iir.Elliptic(f.order, r, math.Max(a, r+1))
+	}
+	freq := clamp(float64(cutoff/f.sampleRate), 1e-5, .499)
+	var s []iir.Section
+	if f.highPass {
+		s = iir.HighPass(p, freq)
+	} else {
+		s = iir.LowPass(p, freq)
+	}
+	if len(s) == len(f.sections) {
+		f.setCoefficients(s)
+	} else {
+		f.setSections(s)
+	}
+}
+
+// cascade is a series of biquads.
+type cascade struct {
+	sections []biquad
+}
+
+func (f *cascade) setSections(s []iir.Section) {
+	f.sections = make([]biquad, len(s))
+	f.setCoefficients(s)
+}
+
+// setCoefficients sets the coefficients of the sections, of which there must be as many as before, keeping their state.
+func (f *cascade) setCoefficients(s []iir.Section) {
+	for i, s := range s {
+		b := &f.sections[i]
+		b.b0, b.b1, b.b2 = float32(s.B0), float32(s.B1), float32(s.B2)
+		b.a1, b.a2 = float32(s.A1), float32(s.A2)
+	}
+}
+
+func (f *cascade) process(x float32) float32 {
+	for i := range f.sections {
+		x = f.sections[i].process(x)
+	}
+	return x
+}
@@ -0,0 +1,64 @@
+package iir
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// The Jacobi elliptic functions below take their argument u in units of the quarter period K and are computed by
+// descending Landen transformations, after Orfanidis.
+
+// landen returns the descending Landen sequence of moduli starting from k.
+func landen(k float64) []float64 {
+	var v []float64
+	for k > 1e-15 {
+		kp := math.Sqrt(1 - k*k)
+		k = (k / (1 + kp)) * (k / (1 + kp))
+		v = append(v, k)
+	}
+	return v
+}
+
+// cde returns cd(uK, k).
+func cde(u complex128, k float64) complex128 {
+	return ascend(cmplx.Cos(u*math.Pi/2), landen(k))
+}
+
+// sne returns sn(uK, k).
+func sne(u complex128, k float64) complex128 {
+	return ascend(cmplx.Sin(u*math.Pi/2), landen(k))
+}
+
+func ascend(w complex128, v []float64) complex128 {
+	for i := len(v) - 1; i >= 0; i-- {
+		w = complex(1+v[i], 0) * w / (1 + complex(v[i], 0)*w*w)
+	}
+	return w
+}
+
+// acde returns the inverse of cde.
+func acde(w complex128, k float64) complex128 {
+	v := landen(k)
+	k1 := k
+	for _, kn := range v {
+		w = w / (1 + cmplx.Sqrt(1-w*w*complex(k1*k1, 0))) * complex(2/(1+kn), 0)
+		k1 = kn
+	}
+	return cmplx.Acos(w) * 2 / math.Pi
+}
+
+// asne returns the inverse of sne.
+func asne(w complex128, k float64) complex128 {
+	return 1 - acde(w, k)
+}
+
+// ellipdeg solves the degree equation for the elliptic modulus k of an order n filter with discrimination modulus k1.
+func ellipdeg(n int, k1 float64) float64 {
+	k1p := math.Sqrt(1 - k1*k1)
+	kp := math.Pow(k1p, float64(n))
+	for i := 1; i <= n/2; i++ {
+		s := real(sne(complex(float64(2*i-1)/float64(n), 0), k1p))
+		kp *= s * s * s * s
+	}
+	return math.Sqrt(1 - kp*kp)
+}
@@ -0,0 +1,99 @@
+// Package iir designs infinite impulse response filters as cascades of second-order sections.
+//
+// Design proceeds in two steps: an analog low-pass prototype with its cutoff at 1 rad/s is computed (Butterworth, Chebyshev1,
+// Chebyshev2, Elliptic) and is then transformed into a digital low- or high-pass filter (LowPass, HighPass) with the bilinear transform.
+package iir
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// A ZPK is a transfer function in zero-pole-gain form.
+type ZPK struct {
+	Zeros, Poles []complex128
+	Gain         float64
+}
+
+// Butterworth returns the analog Butterworth low-pass prototype of the given order.
+func Butterworth(order int) ZPK {
+	p := ZPK{Gain: 1}
+	for k := 1; k <= order; k++ {
+		theta := math.Pi * float64(2*k+order-1) / float64(2*order)
+		p.Poles = append(p.Poles, cmplx.Rect(1, theta))
+	}
+	return p
+}
+
+// Chebyshev1 returns the analog Chebyshev type I low-pass prototype of the given order with ripple decibels of passband ripple.
+// The response falls below the ripple band at 1 rad/s.
+func Chebyshev1(order int, ripple float64) ZPK {
+	eps := math.Sqrt(math.Pow(10, ripple/10) - 1)
+	mu := math.Asinh(1/eps) / float64(order)
+	p := ZPK{Gain: 1}
+	for k := 1; k <= order; k++ {
+		s, c := math.Sincos(math.Pi * float64(2*k-1) / float64(2*order))
+		p.Poles = append(p.Poles, complex(-math.Sinh(mu)*s, math.Cosh(mu)*c))
+	}
+	p.Gain = real(prod(p.Poles, 0))
+	if order%2 == 0 {
+		p.Gain /= math.Sqrt(1 + eps*eps)
+	}
+	return p
+}
+
+// Chebyshev2 returns the analog Chebyshev type II (inverse Chebyshev) low-pass prototype of the given order with atten decibels
+// of stopband attenuation.
+// The stopband begins at 1 rad/s.
+func Chebyshev2(order int, atten float64) ZPK {
+	eps := math.Sqrt(math.Pow(10, atten/10) - 1)
+	mu := math.Asinh(eps) / float64(order)
+	p := ZPK{Gain: 1}
+	for k := 1; k <= order; k++ {
+		s, c := math.Sincos(math.Pi * float64(2*k-1) / float64(2*order))
+		p.Poles = append(p.Poles, 1/complex(-math.Sinh(mu)*s, math.Cosh(mu)*c))
+		if 2*k-1 != order {
+			p.Zeros = append(p.Zeros, complex(0, 1/c))
+		}
+	}
+	p.Gain = real(prod(p.Poles, 0) / prod(p.Zeros, 0))
+	return p
+}
+
+// Elliptic returns the analog elliptic (Cauer) low-pass prototype of the given order with ripple decibels of passband ripple
+// and atten decibels of stopband attenuation.
+// The response falls below the ripple band at 1 rad/s.
+func Elliptic(order int, ripple, atten float64) ZPK {
+	// After S. J. Orfanidis, Lecture Notes on Elliptic Filter Design (2006).
+	ep := math.Sqrt(math.Pow(10, ripple/10) - 1)
+	es := math.Sqrt(math.Pow(10, atten/10) - 1)
+	k1 := ep / es
+	k := ellipdeg(order, k1)
+	v0 := real(-1i*asne(complex(0, 1/ep), k1)) / float64(order)
+
+	p := ZPK{}
+	for i := 1; i <= order/2; i++ {
+		u := float64(2*i-1) / float64(order)
+		z := complex(0, 1/(k*real(cde(complex(u, 0), k))))
+		pole := 1i * cde(complex(u, -v0), k)
+		p.Zeros = append(p.Zeros, z, cmplx.Conj(z))
+		p.Poles = append(p.Poles, pole, cmplx.Conj(pole))
+	}
+	if order%2 == 1 {
+		p.Poles = append(p.Poles, complex(real(1i*sne(complex(0, v0), k)), 0))
+	}
+	p.Gain = real(prod(p.Poles, 0) / prod(p.Zeros, 0))
+	if order%2 == 0 {
+		p.Gain /= math.Sqrt(1 + ep*ep)
+	}
+	return p
+}
+
+// prod returns the product of s-x for x in xs.
+func prod(xs []complex128, s complex128) complex128 {
+	p := complex(1, 0)
+	for _, x := range xs {
+		p *= s - x
+	}
+	return p
+}
@@ -0,0 +1,140 @@
+package iir
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+)
+
+// A Section is a second-order section (biquad) with transfer function (B0 + B1/z + B2/z²) / (1 + A1/z + A2/z²).
+type Section struct {
+	B0, B1, B2, A1, A2 float64
+}
+
+// LowPass transforms the analog low-pass prototype p into a digital low-pass filter with its cutoff at freq,
+// given as a fraction of the sample rate (0..0.5).
+func LowPass(p ZPK, freq float64) []Section {
+	wc := prewarp(freq)
+	d := ZPK{Gain: p.Gain}
+	for _, z := range p.Zeros {
+		d.Zeros = append(d.Zeros, z*complex(wc, 0))
+	}
+	for _, x := range p.Poles {
+		d.Poles = append(d.Poles, x*complex(wc, 0))
+	}
+	d.Gain *= math.Pow(wc, float64(len(p.Poles)-len(p.Zeros)))
+	return Sections(bilinear(d))
+}
+
+// HighPass transforms the analog low-pass prototype p into a digital high-pass filter with its cutoff at freq,
+// given as a fraction of the sample rate (0..0.5).
+func HighPass(p ZPK, freq float64) []Section {
+	wc := complex(prewarp(freq), 0)
+	d := ZPK{Gain: p.Gain * real(prod(p.Zeros, 0)/prod(p.Poles, 0))}
+	for _, z := range p.Zeros {
+		d.Zeros = append(d.Zeros, wc/z)
+	}
+	for _, x := range p.Poles {
+		d.Poles = append(d.Poles, wc/x)
+	}
+	for i := len(p.Zeros); i < len(p.Poles); i++ {
+		d.Zeros = append(d.Zeros, 0)
+	}
+	return Sections(bilinear(d))
+}
+
+// prewarp returns the analog frequency (rad/s, for a sample period of 1) that the bilinear transform maps to freq.
+func prewarp(freq float64) float64 {
+	return 2 * math.Tan(math.Pi*math.Min(math.Max(freq, 1e-9), .4999))
+}
+
+// bilinear maps an analog filter to a digital one with s = 2(z-1)/(z+1).
+func bilinear(p ZPK) ZPK {
+	d := ZPK{Gain: p.Gain * real(prod(p.Zeros, 2)/prod(p.Poles, 2))}
+	for _, z := range p.Zeros {
+		d.Zeros = append(d.Zeros, (2+z)/(2-z))
+	}
+	for _, x := range p.Poles {
+		d.Poles = append(d.Poles, (2+x)/(2-x))
+	}
+	for i := len(p.Zeros); i < len(p.Poles); i++ {
+		d.Zeros = append(d.Zeros, -1)
+	}
+	return d
+}
+
+// Sections factors the digital filter p into second-order sections.
+// Complex poles and zeros must come in conjugate pairs.
+// Poles nearest the unit circle are paired with their nearest zeros, and the gain is applied to the first section.
+func Sections(p ZPK) []Section {
+	poles := groups(p.Poles)
+	zeros := groups(p.Zeros)
+	sort.SliceStable(poles, func(i, j int) bool {
+		return cmplx.Abs(poles[i][0]) > cmplx.Abs(poles[j][0])
+	})
+
+	var s []Section
+	add := func(zs, ps []complex128) {
+		b0, b1, b2 := poly(zs)
+		_, a1, a2 := poly(ps)
+		s = append(s, Section{b0, b1, b2, a1, a2})
+	}
+	for _, g := range poles {
+		if len(zeros) == 0 {
+			add(nil, g)
+			continue
+		}
+		best := 0
+		for i, z := range zeros {
+			if cmplx.Abs(z[0]-g[0]) < cmplx.Abs(zeros[best][0]-g[0]) {
+				best = i
+			}
+		}
+		add(zeros[best], g)
+		zeros = append(zeros[:best], zeros[best+1:]...)
+	}
+	for _, z := range zeros {
+		add(z, nil)
+	}
+	if len(s) > 0 {
+		s[0].B0 *= p.Gain
+		s[0].B1 *= p.Gain
+		s[0].B2 *= p.Gain
+	}
+	return s
+}
+
+// groups splits xs into conjugate pairs and pairs of real values, with a possible single real value left over.
+// Values whose imaginary part is negligible are taken to be real.
+func groups(xs []complex128) [][]complex128 {
+	var g [][]complex128
+	var reals []complex128
+	for _, x := range xs {
+		switch {
+		case math.Abs(imag(x)) <= 1e-9*cmplx.Abs(x):
+			reals = append(reals, complex(real(x), 0))
+		case imag(x) > 0:
+			g = append(g, []complex128{x, cmplx.Conj(x)})
+		}
+	}
+	sort.Slice(reals, func(i, j int) bool { return math.Abs(real(reals[i])) > math.Abs(real(reals[j])) })
+	for len(reals) >= 2 {
+		g = append(g, reals[:2])
+		reals = reals[2:]
+	}
+	if len(reals) == 1 {
+		g = append(g, reals)
+	}
+	return g
+}
+
+// poly returns the real coefficients of the monic polynomial in 1/z with up to two roots xs.
+func poly(xs []complex128) (c0, c1, c2 float64) {
+	switch len(xs) {
+	case 0:
+		return 1, 0, 0
+	case 1:
+		return 1, -real(xs[0]), 0
+	}
+	return 1, -real(xs[0] + xs[1]), real(xs[0] * xs[1])
+}
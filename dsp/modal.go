@@ -0,0 +1,173 @@
+package dsp
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ModalBank is a bank of tuned two-pole resonators, each ringing at one of the modes of vibration of an object when struck by x.
+// Freq is the frequency in Hz of the fundamental, of which the mode frequencies are multiples.
+// Modes above the Nyquist frequency are silent.
+//
+// Modes may be set, for example to one of the presets ModalBar, ModalPlate or ModalBell or by Load, before Init.
+// Otherwise, if File is set, Init loads the modes from it, panicking if it cannot be read, and if not, it uses ModalBar.
+// Init copies Modes, which may then be changed between calls to Process; changes to existing modes take effect when freq next changes.
+type ModalBank struct {
+	File  string
+	Modes []Mode
+
+	sampleRate float32
+	freq       float32
+	resonators []resonator
+}
+
+// A Mode is a mode of vibration of a ModalBank.
+type Mode struct {
+	Freq  float32 // A multiple of the fundamental frequency.
+	Decay float32 // The decay time (RT60) in seconds.
+	Gain  float32
+}
+
+// Mode presets, from the ratios of the modes of vibration of ideal objects.
+var (
+	// ModalBar is a uniform bar, free at both ends, like that of a xylophone or glockenspiel.
+	ModalBar = []Mode{
+		{1, 1.5, 1},
+		{2.756, 1, .6},
+		{5.404, .7, .4},
+		{8.933, .5, .25},
+		{13.345, .35, .15},
+		{18.638, .25, .1},
+	}
+
+	// ModalPlate is a square plate, simply supported at its edges.
+	ModalPlate = []Mode{
+		{1, 3, 1},
+		{2.5, 2.5, .8},
+		{4, 2, .6},
+		{5, 1.8, .5},
+		{6.5, 1.5, .4},
+		{8.5, 1.2, .3},
+		{9, 1.1, .3},
+		{10, 1, .25},
+		{12.5, .8, .2},
+		{13, .8, .2},
+	}
+
+	// ModalBell is a tuned church bell, with the fundamental at its prime partial; the hum note is an octave below.
+	ModalBell = []Mode{
+		{.5, 10, .5},
+		{1, 6, 1},
+		{1.2, 5, .8},
+		{1.5, 3, .5},
+		{2, 4, .9},
+		{2.5, 2.5, .4},
+		{2.67, 2.5, .3},
+		{3, 2, .3},
+		{4, 1.5, .2},
+	}
+)
+
+// Load reads modes from a text file.
+// Each line holds the frequency (as a multiple of the fundamental), decay time and gain of one mode, separated by spaces.
+// Blank lines and lines starting with # are ignored.
+func (m *ModalBank) Load(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var modes []Mode
+	s := bufio.NewScanner(f)
+	for line := 1; s.Scan(); line++ {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) != 3 {
+			return fmt.Errorf("%s:%d: expected frequency, decay and gain", name, line)
+		}
+		var v [3]float32
+		for i, field := range fields {
+			x, err := strconv.ParseFloat(field, 32)
+			if err != nil {
+				return fmt.Errorf("%s:%d: %v", name, line, err)
+			}
+			v[i] = float32(x)
+		}
+		modes = append(modes, Mode{v[0], v[1], v[2]})
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+	m.Modes = modes
+	return nil
+}
+
+func (m *ModalBank) Init(c Config) {
+	if m.Modes == nil {
+		if m.File != "" {
+			if err := m.Load(m.File); err != nil {
+				panic(err)
+			}
+		} else {
+			m.Modes = ModalBar
+		}
+	}
+	// Copy the modes so that changing them does not change a preset shared by other banks.
+	m.Modes = append([]Mode(nil), m.Modes...)
+	m.sampleRate = c.SampleRate
+	m.freq = -1
+	m.resonators = make([]resonator, len(m.Modes))
+}
+
+func (m *ModalBank) Process(x, freq float32) float32 {
+	if n := len(m.Modes); n != len(m.resonators) {
+		if n < len(m.resonators) {
+			m.resonators = m.resonators[:n]
+		} else {
+			m.resonators = append(m.resonators, make([]resonator, n-len(m.resonators))...)
+		}
+		m.freq = -1
+	}
+	if freq != m.freq {
+		m.freq = freq
+		for i, mode := range m.Modes {
+			m.resonators[i].design(mode.Freq*freq, mode.Decay, mode.Gain, m.sampleRate)
+		}
+	}
+	y := float32(0)
+	for i := range m.resonators {
+		y += m.resonators[i].process(x)
+	}
+	return y
+}
+
+// resonator is a two-pole resonator whose impulse response is an exponentially decaying sine.
+type resonator struct {
+	b0, a1, a2 float32
+	y1, y2     float32
+}
+
+func (r *resonator) design(freq, decay, gain, sampleRate float32) {
+	if freq <= 0 || freq >= sampleRate/2 || decay <= 0 {
+		r.b0, r.a1, r.a2 = 0, 0, 0
+		return
+	}
+	w := 2 * math.Pi * float64(freq/sampleRate)
+	p := float64(decayGain(1/sampleRate, decay))
+	r.b0 = gain * float32(math.Sin(w))
+	r.a1 = float32(-2 * p * math.Cos(w))
+	r.a2 = float32(p * p)
+}
+
+func (r *resonator) process(x float32) float32 {
+	y := r.b0*x - r.a1*r.y1 - r.a2*r.y2
+	r.y1, r.y2 = y, r.y1
+	return y
+}
@@ -0,0 +1,118 @@
+package dsp
+
+import "math"
+
+// Chorus mixes x with several copies of itself, each delayed by a slowly modulated amount.
+// Rate is the modulation frequency in Hz; depth (0..1) scales the modulation; feedback (-1..1) recirculates the delayed signal;
+// mix (0..1) is the fraction of delayed signal in the output.
+type Chorus struct {
+	delay Delay
+	lfo   phasor
+	wet   float32
+}
+
+const (
+	chorusVoices = 3
+	chorusDelay  = .02
+	chorusSweep  = .01
+)
+
+func (m *Chorus) Init(c Config) {
+	m.delay.Init(c)
+	m.lfo.init(c)
+	m.wet = 0
+}
+
+func (m *Chorus) Process(x, rate, depth, feedback, mix float32) float32 {
+	t, _ := m.lfo.next(rate)
+	depth = clampDepth(depth)
+	m.delay.Write(x + clampFeedback(feedback)*m.wet)
+	m.wet = 0
+	for i := 0; i < chorusVoices; i++ {
+		lfo := float32(math.Sin(2 * math.Pi * float64(t+float32(i)/chorusVoices)))
+		m.wet += m.delay.Read(chorusDelay + depth*chorusSweep*lfo)
+	}
+	m.wet /= chorusVoices
+	return x + mix*(m.wet-x)
+}
+
+// Flanger mixes x with a copy of itself delayed by a short, slowly modulated amount.
+// Rate is the modulation frequency in Hz; depth (0..1) scales the modulation; feedback (-1..1) recirculates the delayed signal;
+// mix (0..1) is the fraction of delayed signal in the output (0.5 gives the deepest notches).
+type Flanger struct {
+	delay Delay
+	lfo   phasor
+	wet   float32
+}
+
+const (
+	flangerDelay = .0001
+	flangerSweep = .005
+)
+
+func (m *Flanger) Init(c Config) {
+	m.delay.Init(c)
+	m.lfo.init(c)
+	m.wet = 0
+}
+
+func (m *Flanger) Process(x, rate, depth, feedback, mix float32) float32 {
+	t, _ := m.lfo.next(rate)
+	lfo := float32(1-math.Cos(2*math.Pi*float64(t))) / 2
+	depth = clampDepth(depth)
+	m.delay.Write(x + clampFeedback(feedback)*m.wet)
+	m.wet = m.delay.Read(flangerDelay + depth*flangerSweep*lfo)
+	return x + mix*(m.wet-x)
+}
+
+// Phaser passes x through a cascade of first-order allpass filters whose break frequency is slowly modulated, and mixes the result with x.
+// Rate is the modulation frequency in Hz; depth (0..1) scales the modulation; feedback (-1..1) recirculates the filtered signal;
+// mix (0..1) is the fraction of filtered signal in the output (0.5 gives the deepest notches).
+type Phaser struct {
+	sampleRate float32
+	lfo        phasor
+	stages     [phaserStages]float32
+	wet        float32
+}
+
+const (
+	phaserStages = 6
+	phaserFreq   = 630
+	phaserRange  = 6.3
+)
+
+func (m *Phaser) Init(c Config) {
+	*m = Phaser{sampleRate: c.SampleRate}
+	m.lfo.init(c)
+}
+
+func (m *Phaser) Process(x, rate, depth, feedback, mix float32) float32 {
+	t, _ := m.lfo.next(rate)
+	lfo := math.Sin(2 * math.Pi * float64(t))
+	freq := phaserFreq * math.Pow(phaserRange, float64(clampDepth(depth))*lfo)
+	a := float32(allpassCoef(freq, m.sampleRate))
+
+	y := x + clampFeedback(feedback)*m.wet
+	for i := range m.stages {
+		z := &m.stages[i]
+		out := a*y + *z
+		*z = y - a*out
+		y = out
+	}
+	m.wet = y
+	return x + mix*(y-x)
+}
+
+// allpassCoef returns the coefficient of a first-order allpass filter with a 90 degree phase shift at freq.
+func allpassCoef(freq float64, sampleRate float32) float64 {
+	w := math.Tan(math.Pi * clamp(freq, 1e-3, .499*float64(sampleRate)) / float64(sampleRate))
+	return (w - 1) / (w + 1)
+}
+
+func clampFeedback(feedback float32) float32 {
+	return float32(clamp(float64(feedback), -.99, .99))
+}
+
+func clampDepth(depth float32) float32 {
+	return float32(clamp(float64(depth), 0, 1))
+}
@@ -1,6 +1,7 @@
 package dsp
 
 import (
+	"math/bits"
 	"math/rand"
 )
 
@@ -15,3 +16,121 @@ func (n *WhiteNoise) Init(c Config) {
 func (n *WhiteNoise) Process() float32 {
 	return 2*n.rand.Float32() - 1
 }
+
+// PinkNoise has equal power per octave (-3dB/octave).
+// It uses the Voss-McCartney algorithm and stays within -1..1.
+type PinkNoise struct {
+	rand  *rand.Rand
+	rows  [pinkRows]float32
+	sum   float32
+	count uint32
+}
+
+const pinkRows = 16
+
+func (n *PinkNoise) Init(c Config) {
+	n.rand = c.GetRand()
+	n.sum = 0
+	n.count = 0
+	for i := range n.rows {
+		n.rows[i] = 2*n.rand.Float32() - 1
+		n.sum += n.rows[i]
+	}
+}
+
+func (n *PinkNoise) Process() float32 {
+	n.count++
+	if i := bits.TrailingZeros32(n.count); i < pinkRows {
+		x := 2*n.rand.Float32() - 1
+		n.sum += x - n.rows[i]
+		n.rows[i] = x
+	}
+	return (n.sum + 2*n.rand.Float32() - 1) / (pinkRows + 1)
+}
+
+// BrownNoise (or red noise) falls off at -6dB/octave.
+type BrownNoise struct {
+	rand *rand.Rand
+	y    float32
+}
+
+func (n *BrownNoise) Init(c Config) {
+	n.rand = c.GetRand()
+	n.y = 0
+}
+
+func (n *BrownNoise) Process() float32 {
+	n.y = (n.y + .02*(2*n.rand.Float32()-1)) / 1.02
+	return 3.5 * n.y
+}
+
+// BlueNoise rises at 3dB/octave.
+type BlueNoise struct {
+	pink PinkNoise
+	prev float32
+}
+
+func (n *BlueNoise) Init(c Config) {
+	n.pink.Init(c)
+	n.prev = 0
+}
+
+func (n *BlueNoise) Process() float32 {
+	x := n.pink.Process()
+	y := x - n.prev
+	n.prev = x
+	return 4 * y
+}
+
+// VelvetNoise is a sparse sequence of impulses of random sign, one at a random position within each period of 1/density seconds.
+type VelvetNoise struct {
+	rand       *rand.Rand
+	sampleRate float32
+	i, period  int
+	pos        int
+	sign       float32
+}
+
+func (n *VelvetNoise) Init(c Config) {
+	n.rand = c.GetRand()
+	n.sampleRate = c.SampleRate
+	n.i = 0
+	n.period = 0
+}
+
+func (n *VelvetNoise) Process(density float32) float32 {
+	if n.i >= n.period {
+		n.i = 0
+		n.period = 0
+		if density > 0 {
+			n.period = int(n.sampleRate / density)
+		}
+		if n.period < 1 {
+			n.period = 1
+		}
+		n.pos = n.rand.Intn(n.period)
+		n.sign = 1
+		if n.rand.Intn(2) == 0 {
+			n.sign = -1
+		}
+	}
+	i := n.i
+	n.i++
+	if i == n.pos && density > 0 {
+		return n.sign
+	}
+	return 0
+}
+
+// GaussianNoise is white noise with a standard normal distribution.
+type GaussianNoise struct {
+	rand *rand.Rand
+}
+
+func (n *GaussianNoise) Init(c Config) {
+	n.rand = c.GetRand()
+}
+
+func (n *GaussianNoise) Process() float32 {
+	return float32(n.rand.NormFloat64())
+}
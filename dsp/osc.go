@@ -0,0 +1,148 @@
+package dsp
+
+import "math"
+
+// Sine is a sine wave oscillator.
+type Sine struct {
+	phasor
+}
+
+func (o *Sine) Init(c Config) {
+	o.phasor.init(c)
+}
+
+func (o *Sine) Process(freq float32) float32 {
+	t, _ := o.next(freq)
+	return float32(math.Sin(2 * math.Pi * float64(t)))
+}
+
+// Saw is a band-limited rising sawtooth oscillator.
+type Saw struct {
+	phasor
+}
+
+func (o *Saw) Init(c Config) {
+	o.phasor.init(c)
+}
+
+func (o *Saw) Process(freq float32) float32 {
+	t, dt := o.next(freq)
+	return 2*t - 1 - 2*polyBLEP(t, dt)
+}
+
+// Square is a band-limited square wave oscillator.
+type Square struct {
+	phasor
+}
+
+func (o *Square) Init(c Config) {
+	o.phasor.init(c)
+}
+
+func (o *Square) Process(freq float32) float32 {
+	t, dt := o.next(freq)
+	return pulse(t, dt, .5)
+}
+
+// Pulse is a band-limited pulse wave oscillator.
+// Width is the fraction (0..1) of each cycle spent high.
+type Pulse struct {
+	phasor
+}
+
+func (o *Pulse) Init(c Config) {
+	o.phasor.init(c)
+}
+
+func (o *Pulse) Process(freq, width float32) float32 {
+	t, dt := o.next(freq)
+	return pulse(t, dt, width)
+}
+
+// Triangle is a band-limited triangle wave oscillator.
+type Triangle struct {
+	phasor
+}
+
+func (o *Triangle) Init(c Config) {
+	o.phasor.init(c)
+}
+
+func (o *Triangle) Process(freq float32) float32 {
+	t, dt := o.next(freq)
+	x := 4*abs(t-.5) - 1
+	return x + 8*dt*(polyBLAMP(wrap(t+.5), dt)-polyBLAMP(t, dt))
+}
+
+type phasor struct {
+	sampleRate float32
+	phase      float32
+}
+
+func (p *phasor) init(c Config) {
+	p.sampleRate = c.SampleRate
+	p.phase = 0
+}
+
+// next returns the current phase (0..1) and the absolute phase increment, then advances the phase.
+func (p *phasor) next(freq float32) (t, dt float32) {
+	t = p.phase
+	dt = freq / p.sampleRate
+	p.phase = wrap(p.phase + dt)
+	dt = abs(dt)
+	if dt > .5 {
+		dt = .5
+	}
+	return t, dt
+}
+
+func pulse(t, dt, width float32) float32 {
+	if width <= 0 {
+		return -1
+	}
+	if width >= 1 {
+		return 1
+	}
+	x := float32(-1)
+	if t < width {
+		x = 1
+	}
+	return x + 2*polyBLEP(t, dt) - 2*polyBLEP(wrap(t-width), dt)
+}
+
+// polyBLEP returns the polynomial band-limited step residual for a unit step at phase 0.
+func polyBLEP(t, dt float32) float32 {
+	if t < dt {
+		t = 1 - t/dt
+		return -t * t / 2
+	}
+	if t > 1-dt {
+		t = 1 + (t-1)/dt
+		return t * t / 2
+	}
+	return 0
+}
+
+// polyBLAMP returns the polynomial band-limited ramp residual for a unit change in slope per sample at phase 0.
+func polyBLAMP(t, dt float32) float32 {
+	if t < dt {
+		t = 1 - t/dt
+		return t * t * t / 6
+	}
+	if t > 1-dt {
+		t = 1 + (t-1)/dt
+		return t * t * t / 6
+	}
+	return 0
+}
+
+func wrap(t float32) float32 {
+	return t - float32(math.Floor(float64(t)))
+}
+
+func abs(x float32) float32 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,49 @@
+package dsp
+
+import "math"
+
+// resample converts x from one sample rate to another with windowed-sinc interpolation.
+// It is meant for preparing tables and impulse responses in Init, not for running in Process.
+func resample(x []float32, from, to float32) []float32 {
+	if from == to || from <= 0 || to <= 0 || len(x) == 0 {
+		return x
+	}
+	const zeroCrossings = 32
+	ratio := float64(to) / float64(from)
+	cutoff := math.Min(1, ratio) // Relative to the input Nyquist frequency.
+	halfWidth := zeroCrossings / cutoff
+	y := make([]float32, int(math.Ceil(float64(len(x))*ratio)))
+	for i := range y {
+		t := float64(i) / ratio
+		lo := int(math.Ceil(t - halfWidth))
+		hi := int(math.Floor(t + halfWidth))
+		if lo < 0 {
+			lo = 0
+		}
+		if hi >= len(x) {
+			hi = len(x) - 1
+		}
+		sum := 0.
+		for j := lo; j <= hi; j++ {
+			d := float64(j) - t
+			sum += float64(x[j]) * cutoff * sinc(cutoff*d) * blackman(d/halfWidth)
+		}
+		y[i] = float32(sum)
+	}
+	return y
+}
+
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	return math.Sin(math.Pi*x) / (math.Pi * x)
+}
+
+// blackman is the Blackman window on -1..1.
+func blackman(x float64) float64 {
+	if x <= -1 || x >= 1 {
+		return 0
+	}
+	return .42 + .5*math.Cos(math.Pi*x) + .08*math.Cos(2*math.Pi*x)
+}
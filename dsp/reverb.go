@@ -0,0 +1,155 @@
+package dsp
+
+import "math"
+
+// Freeverb is a Schroeder-Moorer reverb after Jezar's Freeverb: eight parallel damped comb filters followed by four series allpass filters.
+// Size (0.01..10) scales the delay lengths (1 gives the original tuning); decay is the reverberation time (RT60) in seconds;
+// damping (0..1) attenuates high frequencies in the tail; mix (0..1) is the fraction of reverberated signal in the output.
+type Freeverb struct {
+	sampleRate   float32
+	combs        [8]comb
+	allpasses    [4]Delay
+	size, decay  float32
+	gains        [8]float32
+	gainsUpdated bool
+}
+
+// Freeverb delay lengths in seconds (samples at 44.1kHz).
+var (
+	freeverbCombs     = [8]float32{1116. / 44100, 1188. / 44100, 1277. / 44100, 1356. / 44100, 1422. / 44100, 1491. / 44100, 1557. / 44100, 1617. / 44100}
+	freeverbAllpasses = [4]float32{556. / 44100, 441. / 44100, 341. / 44100, 225. / 44100}
+)
+
+func (r *Freeverb) Init(c Config) {
+	r.sampleRate = c.SampleRate
+	for i := range r.combs {
+		r.combs[i].Init(c)
+	}
+	for i := range r.allpasses {
+		r.allpasses[i].Init(c)
+	}
+	r.gainsUpdated = false
+}
+
+func (r *Freeverb) Process(x, size, damping, decay, mix float32) float32 {
+	size = reverbSize(size)
+	if !r.gainsUpdated || size != r.size || decay != r.decay {
+		r.size, r.decay = size, decay
+		for i, t := range freeverbCombs {
+			r.gains[i] = decayGain(t*size, decay)
+		}
+		r.gainsUpdated = true
+	}
+
+	const inputGain = .015
+	in := inputGain * x
+	wet := float32(0)
+	for i := range r.combs {
+		wet += r.combs[i].process(in, freeverbCombs[i]*size, r.gains[i], damping)
+	}
+	for i := range r.allpasses {
+		d := &r.allpasses[i]
+		y := d.FeedbackRead(freeverbAllpasses[i] * size)
+		d.Write(wet + y/2)
+		wet = y - wet
+	}
+	return x + mix*(wet-x)
+}
+
+// comb is a feedback comb filter with a one-pole low-pass filter in the loop.
+type comb struct {
+	Delay
+	lp float32
+}
+
+func (c *comb) Init(cfg Config) {
+	c.Delay.Init(cfg)
+	c.lp = 0
+}
+
+func (c *comb) process(x, t, gain, damping float32) float32 {
+	y := c.FeedbackRead(t)
+	c.lp = y + damping*(c.lp-y)
+	c.Write(x + gain*c.lp)
+	return y
+}
+
+// FDNReverb is an eight-line feedback delay network reverb with a Hadamard feedback matrix.
+// Size (0.01..10) scales the delay lengths (1 gives lengths from 30 to 75ms); decay is the reverberation time (RT60) in seconds;
+// damping (0..1) attenuates high frequencies in the tail; mix (0..1) is the fraction of reverberated signal in the output.
+type FDNReverb struct {
+	sampleRate   float32
+	lines        [fdnLines]Delay
+	lp           [fdnLines]float32
+	size, decay  float32
+	gains        [fdnLines]float32
+	gainsUpdated bool
+}
+
+const fdnLines = 8
+
+// FDN delay lengths in seconds, chosen to be mutually prime in samples at common sample rates.
+var fdnLengths = [fdnLines]float32{.0297, .0371, .0411, .0437, .0530, .0599, .0677, .0749}
+
+func (r *FDNReverb) Init(c Config) {
+	r.sampleRate = c.SampleRate
+	for i := range r.lines {
+		r.lines[i].Init(c)
+		r.lp[i] = 0
+	}
+	r.gainsUpdated = false
+}
+
+func (r *FDNReverb) Process(x, size, damping, decay, mix float32) float32 {
+	size = reverbSize(size)
+	if !r.gainsUpdated || size != r.size || decay != r.decay {
+		r.size, r.decay = size, decay
+		for i, t := range fdnLengths {
+			r.gains[i] = decayGain(t*size, decay)
+		}
+		r.gainsUpdated = true
+	}
+
+	var s [fdnLines]float32
+	wet := float32(0)
+	for i := range r.lines {
+		y := r.lines[i].FeedbackRead(fdnLengths[i] * size)
+		wet += y
+		r.lp[i] = y + damping*(r.lp[i]-y)
+		s[i] = r.gains[i] * r.lp[i]
+	}
+	hadamard(s[:])
+	for i := range r.lines {
+		r.lines[i].Write(x + s[i])
+	}
+	wet /= fdnLines
+	return x + mix*(wet-x)
+}
+
+// hadamard applies the orthonormal Hadamard transform to x, whose length must be a power of two.
+func hadamard(x []float32) {
+	for h := 1; h < len(x); h *= 2 {
+		for i := 0; i < len(x); i += 2 * h {
+			for j := i; j < i+h; j++ {
+				x[j], x[j+h] = x[j]+x[j+h], x[j]-x[j+h]
+			}
+		}
+	}
+	scale := float32(1 / math.Sqrt(float64(len(x))))
+	for i := range x {
+		x[i] *= scale
+	}
+}
+
+// reverbSize limits size so that the delay lines stay within a second or so.
+func reverbSize(size float32) float32 {
+	return float32(clamp(float64(size), .01, 10))
+}
+
+// decayGain returns the gain that makes a recirculating delay of t seconds decay by 60dB in rt60 seconds.
+func decayGain(t, rt60 float32) float32 {
+	if rt60 <= 0 {
+		return 0
+	}
+	return float32(math.Pow(10, -3*float64(t/rt60)))
+}
@@ -0,0 +1,124 @@
+package dsp
+
+import "math"
+
+// SamplePlayer plays a recorded sample each time trigger rises above 0.
+// Rate is the playback speed (1 plays at the original pitch; negative rates play backwards from the end).
+// Loop start and end are positions in seconds; if end is not after start, the loop extends to the end of the sample.
+// Loop mode is 0 to play once, 1 to loop forwards, or 2 to loop back and forth.
+// End is 1 for the sample at which playback reaches the end of the sample or the loop, and 0 otherwise.
+//
+// Samples and SampleRate (their sample rate) may be set, for example by Load, before Init.
+// Otherwise, if File is set, Init loads the samples from it, panicking if it cannot be read.
+type SamplePlayer struct {
+	File       string
+	Samples    []float32
+	SampleRate float32
+
+	rate    float32 // of the samples
+	step    float64 // source samples per output sample at unit rate
+	trigger bool
+	playing bool
+	pos     float64
+	dir     float64
+}
+
+const (
+	playOnce = iota
+	playLoop
+	playPingPong
+)
+
+// Load reads the samples from a WAV file, mixing multiple channels down to mono.
+func (p *SamplePlayer) Load(name string) error {
+	samples, rate, err := readWAV(name)
+	if err != nil {
+		return err
+	}
+	p.Samples, p.SampleRate = samples, rate
+	return nil
+}
+
+func (p *SamplePlayer) Init(c Config) {
+	if p.Samples == nil && p.File != "" {
+		if err := p.Load(p.File); err != nil {
+			panic(err)
+		}
+	}
+	p.rate = p.SampleRate
+	if p.rate <= 0 {
+		p.rate = c.SampleRate
+	}
+	p.step = float64(p.rate / c.SampleRate)
+	p.trigger = false
+	p.playing = false
+}
+
+func (p *SamplePlayer) Process(trigger, rate, loopStart, loopEnd, loopMode float32) (y, end float32) {
+	n := float64(len(p.Samples))
+	on := trigger > 0
+	if on && !p.trigger {
+		p.playing = true
+		p.pos, p.dir = 0, 1
+		if rate < 0 {
+			p.pos = n - 1
+		}
+	}
+	p.trigger = on
+	if !p.playing {
+		return 0, 0
+	}
+
+	start := clamp(float64(loopStart*p.rate), 0, n)
+	stop := float64(loopEnd * p.rate)
+	if stop <= start || stop > n {
+		stop = n
+	}
+	mode := int(round(loopMode))
+	if stop-start < 1 {
+		mode = playOnce
+	}
+
+	wrap := mode == playLoop && p.pos >= start && p.pos < stop
+	y = p.read(p.pos, wrap, start, stop)
+
+	v := p.dir * float64(rate) * p.step
+	p.pos += v
+	switch {
+	case mode == playLoop && v > 0 && p.pos >= stop:
+		p.pos -= stop - start
+		end = 1
+	case mode == playLoop && v < 0 && p.pos < start:
+		p.pos += stop - start
+		end = 1
+	case mode == playPingPong && v > 0 && p.pos >= stop:
+		p.pos = math.Max(start, 2*stop-p.pos)
+		p.dir = -p.dir
+		end = 1
+	case mode == playPingPong && v < 0 && p.pos < start:
+		p.pos = math.Min(stop, 2*start-p.pos)
+		p.dir = -p.dir
+		end = 1
+	case p.pos >= n || p.pos < 0:
+		p.playing = false
+		end = 1
+	}
+	return y, end
+}
+
+// read interpolates the samples at pos.
+// If wrap is set, the points beyond the end of the loop are taken from its start.
+func (p *SamplePlayer) read(pos float64, wrap bool, start, stop float64) float32 {
+	i, f := math.Modf(pos)
+	at := func(j int) float32 {
+		if wrap && float64(j) >= stop {
+			j -= int(stop - start)
+		}
+		if j < 0 || j >= len(p.Samples) {
+			return 0
+		}
+		return p.Samples[j]
+	}
+	j := int(i)
+	return interp3(float32(f), at(j-1), at(j), at(j+1), at(j+2))
+}
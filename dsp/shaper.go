@@ -0,0 +1,209 @@
+package dsp
+
+import "math"
+
+// Tanh saturates x smoothly to -1..1.
+func Tanh(x float32) float32 {
+	return float32(math.Tanh(float64(x)))
+}
+
+// SoftClip saturates x to -2/3..2/3 with the cubic x-x³/3.
+func SoftClip(x float32) float32 {
+	return float32(softClip(float64(x)))
+}
+
+// HardClip clips x to -1..1.
+func HardClip(x float32) float32 {
+	return float32(hardClip(float64(x)))
+}
+
+// Foldback reflects x back and forth between -1 and 1.
+func Foldback(x float32) float32 {
+	return float32(foldback(float64(x)))
+}
+
+// Tube is an asymmetric tanh saturator; bias shifts the operating point, adding even harmonics.
+// The output is offset so that Tube(0, bias) == 0.
+func Tube(x, bias float32) float32 {
+	b := float64(bias)
+	return float32(math.Tanh(float64(x)+b) - math.Tanh(b))
+}
+
+// Chebyshev returns the Chebyshev polynomial of the first kind of the given order (rounded) at x clipped to -1..1.
+// Driven with a full-scale sine, it produces only the harmonic of that order.
+func Chebyshev(x, order float32) float32 {
+	x = float32(hardClip(float64(x)))
+	n := int(order + .5)
+	if n <= 0 {
+		return 1
+	}
+	t0, t1 := float32(1), x
+	for i := 1; i < n; i++ {
+		t0, t1 = t1, 2*x*t1-t0
+	}
+	return t1
+}
+
+// Polynomial evaluates the polynomial with coefficients Coefs (constant term first).
+// Coefs must be set before Init; without them, x is passed through unchanged.
+type Polynomial struct {
+	Coefs []float32
+}
+
+func (p *Polynomial) Init(c Config) {
+	if len(p.Coefs) == 0 {
+		p.Coefs = []float32{0, 1}
+	}
+}
+
+func (p *Polynomial) Process(x float32) float32 {
+	y := float32(0)
+	for i := len(p.Coefs) - 1; i >= 0; i-- {
+		y = y*x + p.Coefs[i]
+	}
+	return y
+}
+
+// TanhADAA is Tanh with first-order antiderivative anti-aliasing.
+// Like all ADAA nodes, it delays its input by half a sample.
+type TanhADAA struct {
+	adaa
+}
+
+func (s *TanhADAA) Init(c Config) {
+	s.adaa = adaa{}
+}
+
+func (s *TanhADAA) Process(x float32) float32 {
+	return s.process(x, math.Tanh, logCosh)
+}
+
+// SoftClipADAA is SoftClip with first-order antiderivative anti-aliasing.
+type SoftClipADAA struct {
+	adaa
+}
+
+func (s *SoftClipADAA) Init(c Config) {
+	s.adaa = adaa{}
+}
+
+func (s *SoftClipADAA) Process(x float32) float32 {
+	return s.process(x, softClip, softClipIntegral)
+}
+
+// HardClipADAA is HardClip with first-order antiderivative anti-aliasing.
+type HardClipADAA struct {
+	adaa
+}
+
+func (s *HardClipADAA) Init(c Config) {
+	s.adaa = adaa{}
+}
+
+func (s *HardClipADAA) Process(x float32) float32 {
+	return s.process(x, hardClip, hardClipIntegral)
+}
+
+// FoldbackADAA is Foldback with first-order antiderivative anti-aliasing.
+type FoldbackADAA struct {
+	adaa
+}
+
+func (s *FoldbackADAA) Init(c Config) {
+	s.adaa = adaa{}
+}
+
+func (s *FoldbackADAA) Process(x float32) float32 {
+	return s.process(x, foldback, foldbackIntegral)
+}
+
+// TubeADAA is Tube with first-order antiderivative anti-aliasing.
+type TubeADAA struct {
+	adaa
+}
+
+func (s *TubeADAA) Init(c Config) {
+	s.adaa = adaa{}
+}
+
+func (s *TubeADAA) Process(x, bias float32) float32 {
+	b := float64(bias)
+	tb := math.Tanh(b)
+	return s.process(x,
+		func(x float64) float64 { return math.Tanh(x+b) - tb },
+		func(x float64) float64 { return logCosh(x+b) - x*tb },
+	)
+}
+
+// adaa implements first-order antiderivative anti-aliasing:
+// the output is the mean of f over the segment between the previous and current inputs, computed from its antiderivative F.
+type adaa struct {
+	x1 float64
+}
+
+func (a *adaa) process(x float32, f, F func(float64) float64) float32 {
+	x0, x1 := float64(x), a.x1
+	a.x1 = x0
+	if d := x0 - x1; math.Abs(d) > 1e-5 {
+		return float32((F(x0) - F(x1)) / d)
+	}
+	return float32(f((x0 + x1) / 2))
+}
+
+func softClip(x float64) float64 {
+	if x >= 1 {
+		return 2. / 3
+	}
+	if x <= -1 {
+		return -2. / 3
+	}
+	return x - x*x*x/3
+}
+
+func softClipIntegral(x float64) float64 {
+	if x := math.Abs(x); x >= 1 {
+		return 2*x/3 - 1./4
+	}
+	return x*x/2 - x*x*x*x/12
+}
+
+func hardClip(x float64) float64 {
+	return clamp(x, -1, 1)
+}
+
+func hardClipIntegral(x float64) float64 {
+	if x := math.Abs(x); x >= 1 {
+		return x - 1./2
+	}
+	return x * x / 2
+}
+
+// foldback is a triangle wave with period 4 passing through the origin with unit slope.
+func foldback(x float64) float64 {
+	p := math.Mod(x+1, 4)
+	if p < 0 {
+		p += 4
+	}
+	if p <= 2 {
+		return p - 1
+	}
+	return 3 - p
+}
+
+// foldbackIntegral is periodic since foldback has zero mean.
+func foldbackIntegral(x float64) float64 {
+	p := math.Mod(x+1, 4)
+	if p < 0 {
+		p += 4
+	}
+	if p <= 2 {
+		return p*p/2 - p
+	}
+	return -p*p/2 + 3*p - 4
+}
+
+// logCosh is the antiderivative of tanh, computed without overflow.
+func logCosh(x float64) float64 {
+	x = math.Abs(x)
+	return x + math.Log1p(math.Exp(-2*x)) - math.Ln2
+}
@@ -0,0 +1,124 @@
+package dsp
+
+import (
+	"math"
+	"math/cmplx"
+
+	"github.com/gordonklaus/dsp/dsp/fft"
+)
+
+// Spectral nodes process 2048-sample frames with a hop of 512 samples.
+// Their output is delayed by Latency samples.
+const (
+	spectralSize = 2048
+	spectralHop  = spectralSize / 4
+)
+
+// SpectralGate removes spectral components quieter than threshold, in decibels relative to a full-scale sinusoid.
+type SpectralGate struct {
+	stft      *fft.STFT
+	fullScale float32
+	threshold float32
+}
+
+func (g *SpectralGate) Init(c Config) {
+	g.stft = fft.NewSTFT(spectralSize, spectralHop, g.processSpectrum)
+	g.fullScale = 0
+	for _, w := range g.stft.Window() {
+		g.fullScale += w / 2
+	}
+}
+
+func (g *SpectralGate) Process(x, threshold float32) float32 {
+	g.threshold = threshold
+	return g.stft.Process(x)
+}
+
+// Latency returns the delay in samples between input and output.
+func (g *SpectralGate) Latency() int { return g.stft.Latency() }
+
+func (g *SpectralGate) processSpectrum(spectrum []complex64) {
+	t := g.fullScale * float32(math.Pow(10, float64(g.threshold)/20))
+	t *= t
+	for i, x := range spectrum {
+		if real(x)*real(x)+imag(x)*imag(x) < t {
+			spectrum[i] = 0
+		}
+	}
+}
+
+// SpectralFreeze passes x through until freeze rises above 0, then sustains the spectrum of that moment indefinitely
+// until freeze falls back.
+type SpectralFreeze struct {
+	stft      *fft.STFT
+	freeze    bool
+	mag       []float32
+	phase     []float64
+	prevPhase []float64
+	dphase    []float64
+}
+
+func (f *SpectralFreeze) Init(c Config) {
+	f.stft = fft.NewSTFT(spectralSize, spectralHop, f.processSpectrum)
+	n := spectralSize/2 + 1
+	f.mag = make([]float32, n)
+	f.phase = make([]float64, n)
+	f.prevPhase = make([]float64, n)
+	f.dphase = make([]float64, n)
+	f.freeze = false
+}
+
+func (f *SpectralFreeze) Process(x, freeze float32) float32 {
+	f.freeze = freeze > 0
+	return f.stft.Process(x)
+}
+
+// Latency returns the delay in samples between input and output.
+func (f *SpectralFreeze) Latency() int { return f.stft.Latency() }
+
+func (f *SpectralFreeze) processSpectrum(spectrum []complex64) {
+	if !f.freeze {
+		for i, x := range spectrum {
+			phase := cmplx.Phase(complex128(x))
+			f.mag[i] = float32(cmplx.Abs(complex128(x)))
+			f.dphase[i] = phase - f.prevPhase[i]
+			f.phase[i] = phase
+			f.prevPhase[i] = phase
+		}
+		return
+	}
+	// Advance each bin's phase at the rate measured before freezing, as a phase vocoder would.
+	for i := range spectrum {
+		f.phase[i] = math.Mod(f.phase[i]+f.dphase[i], 2*math.Pi)
+		spectrum[i] = complex64(cmplx.Rect(float64(f.mag[i]), f.phase[i]))
+	}
+}
+
+// SpectralFilter removes spectral components outside the band from low to high (in Hz).
+type SpectralFilter struct {
+	stft       *fft.STFT
+	sampleRate float32
+	low, high  float32
+}
+
+func (f *SpectralFilter) Init(c Config) {
+	f.stft = fft.NewSTFT(spectralSize, spectralHop, f.processSpectrum)
+	f.sampleRate = c.SampleRate
+}
+
+func (f *SpectralFilter) Process(x, low, high float32) float32 {
+	f.low, f.high = low, high
+	return f.stft.Process(x)
+}
+
+// Latency returns the delay in samples between input and output.
+func (f *SpectralFilter) Latency() int { return f.stft.Latency() }
+
+func (f *SpectralFilter) processSpectrum(spectrum []complex64) {
+	binWidth := f.sampleRate / spectralSize
+	for i := range spectrum {
+		if freq := float32(i) * binWidth; freq < f.low || freq > f.high {
+			spectrum[i] = 0
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package dsp
+
+import (
+	"math"
+	"math/rand"
+)
+
+// KarplusStrong is a plucked string: a burst of noise recirculating through a delay line with a low-pass filter in the loop.
+// The string is plucked when trigger rises above 0.
+// Freq is the pitch in Hz, which is tuned accurately by a fractional-delay allpass filter in the loop.
+// Damping (0..1) shortens the decay of the lowest frequencies from 50 seconds to 50ms;
+// brightness (0..1) controls the brightness of the pluck and how slowly its upper harmonics decay.
+type KarplusStrong struct {
+	sampleRate float32
+	pluck      pluck
+	delay      Delay
+	x1         float32 // loop filter state
+	apx, apy   float32 // tuning allpass state
+
+	freq, damping, brightness float32
+	n                         int
+	a, c, g                   float32
+}
+
+func (s *KarplusStrong) Init(c Config) {
+	*s = KarplusStrong{sampleRate: c.SampleRate}
+	s.pluck.init(c)
+	s.delay.Init(c)
+}
+
+func (s *KarplusStrong) Process(trigger, freq, damping, brightness float32) float32 {
+	freq = stringFreq(freq, s.sampleRate)
+	if s.n == 0 || freq != s.freq || damping != s.damping || brightness != s.brightness {
+		s.freq, s.damping, s.brightness = freq, damping, brightness
+		s.tune()
+	}
+
+	v := s.delay.ReadSample(s.n - 1)
+	lp := s.a*v + (1-s.a)*s.x1
+	s.x1 = v
+	ap := s.c*lp + s.apx - s.c*s.apy
+	s.apx, s.apy = lp, ap
+	y := s.g * ap
+	s.delay.Write(y + s.pluck.next(trigger, s.n, s.brightness))
+	return y
+}
+
+// tune designs the loop so that its total delay at the fundamental is one period.
+// The loop filter is a two-tap FIR (a + (1-a)z⁻¹) whose delay is compensated by the tuning allpass, as is the fractional part of the period.
+func (s *KarplusStrong) tune() {
+	period := float64(s.sampleRate / s.freq)
+	a := .5 + .5*clamp(float64(s.brightness), 0, 1)
+	w := 2 * math.Pi / period
+	re, im := a+(1-a)*math.Cos(w), -(1-a)*math.Sin(w)
+	filterDelay := -math.Atan2(im, re) / w
+
+	// The allpass delay should be between 0.1 and 1.1 samples, where it is most accurate.
+	// Its coefficient gives it exactly that delay at the fundamental.
+	rest := period - filterDelay
+	n := int(rest - .1)
+	if n < 1 {
+		n = 1
+	}
+	d := rest - float64(n)
+	s.n = n
+	s.a = float32(a)
+	s.c = float32(math.Sin((1-d)*w/2) / math.Sin((1+d)*w/2))
+	s.g = stringLoopGain(s.freq, s.damping)
+}
+
+// Waveguide is a digital waveguide string: a pair of delay lines carrying waves in opposite directions between a rigid nut
+// and a bridge whose reflection filter absorbs more of the upper harmonics the less bright it is.
+// X excites the string continuously (for example with filtered noise for a bowed sound) and trigger plucks it with a burst of noise.
+// Freq is the pitch in Hz; position (0..1) is where along the string the output is picked up.
+// Damping (0..1) shortens the decay of the lowest frequencies from 50 seconds to 50ms;
+// brightness (0..1) controls the brightness of the pluck and of the reflection filter.
+type Waveguide struct {
+	sampleRate  float32
+	pluck       pluck
+	right, left Delay
+	lp          float32 // reflection filter state
+
+	freq, damping, brightness float32
+	half                      float32 // delay of each line in seconds
+	a, g                      float32
+}
+
+func (s *Waveguide) Init(c Config) {
+	*s = Waveguide{sampleRate: c.SampleRate}
+	s.pluck.init(c)
+	s.right.Init(c)
+	s.left.Init(c)
+}
+
+func (s *Waveguide) Process(x, trigger, freq, position, damping, brightness float32) float32 {
+	freq = stringFreq(freq, s.sampleRate)
+	if s.half == 0 || freq != s.freq || damping != s.damping || brightness != s.brightness {
+		s.freq, s.damping, s.brightness = freq, damping, brightness
+		s.tune()
+	}
+
+	bridge := s.right.FeedbackRead(s.half)
+	nut := s.left.FeedbackRead(s.half)
+	s.lp = bridge + s.a*(s.lp-bridge)
+	period := int(s.sampleRate / s.freq)
+	s.right.Write(-nut + x + s.pluck.next(trigger, period, s.brightness))
+	s.left.Write(-s.g * s.lp)
+
+	position = float32(clamp(float64(position), 0, 1))
+	return s.right.Read(position*s.half) + s.left.Read((1-position)*s.half)
+}
+
+// tune splits the period, less the delay of the one-pole reflection filter at the fundamental, between the two delay lines.
+func (s *Waveguide) tune() {
+	period := float64(s.sampleRate / s.freq)
+	a := .7 * (1 - clamp(float64(s.brightness), 0, 1))
+	w := 2 * math.Pi / period
+	re, im := 1-a*math.Cos(w), a*math.Sin(w)
+	filterDelay := math.Atan2(im, re) / w
+
+	s.half = float32((period - filterDelay) / 2 / float64(s.sampleRate))
+	s.a = float32(a)
+	s.g = stringLoopGain(s.freq, s.damping)
+}
+
+// stringFreq limits freq to the range that a string of at least a few samples can be tuned to.
+func stringFreq(freq, sampleRate float32) float32 {
+	return float32(clamp(float64(freq), 20, float64(sampleRate)/8))
+}
+
+// stringLoopGain returns the gain that makes a string's lowest frequencies decay in the time set by damping.
+func stringLoopGain(freq, damping float32) float32 {
+	rt60 := 50 * math.Pow(1000, -clamp(float64(damping), 0, 1))
+	return decayGain(1/freq, float32(rt60))
+}
+
+// pluck generates a burst of noise, one period long, each time trigger rises above 0.
+// The less bright the pluck, the more the noise is low-pass filtered.
+// The burst has no DC offset, which would otherwise outlast the rest of the sound.
+type pluck struct {
+	rand    *rand.Rand
+	trigger bool
+	burst   []float32
+}
+
+func (p *pluck) init(c Config) {
+	*p = pluck{rand: c.GetRand()}
+}
+
+func (p *pluck) next(trigger float32, period int, brightness float32) float32 {
+	on := trigger > 0
+	if on && !p.trigger {
+		if cap(p.burst) < period {
+			p.burst = make([]float32, period)
+		}
+		p.burst = p.burst[:period]
+		coef := .1 + .9*float32(clamp(float64(brightness), 0, 1))
+		lp, mean := float32(0), float32(0)
+		for i := range p.burst {
+			lp += coef * (2*p.rand.Float32() - 1 - lp)
+			p.burst[i] = lp
+			mean += lp / float32(period)
+		}
+		for i := range p.burst {
+			p.burst[i] -= mean
+		}
+	}
+	p.trigger = on
+	if len(p.burst) == 0 {
+		return 0
+	}
+	x := p.burst[0]
+	p.burst = p.burst[1:]
+	return x
+}
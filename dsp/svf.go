@@ -0,0 +1,46 @@
+package dsp
+
+import "math"
+
+// SVF is a zero-delay-feedback state-variable filter with simultaneous low-, band-, high-pass and notch outputs.
+// Resonance ranges from 0 (Q=0.5) to 1 (self-oscillation).
+type SVF struct {
+	sampleRate        float32
+	cutoff, resonance float32
+	designed          bool
+	k, a1, a2, a3     float32
+	ic1eq, ic2eq      float32
+}
+
+func (f *SVF) Init(c Config) {
+	*f = SVF{sampleRate: c.SampleRate}
+}
+
+func (f *SVF) Process(x, cutoff, resonance float32) (low, band, high, notch float32) {
+	if !f.designed || cutoff != f.cutoff || resonance != f.resonance {
+		f.design(cutoff, resonance)
+	}
+	v3 := x - f.ic2eq
+	v1 := f.a1*f.ic1eq + f.a2*v3
+	v2 := f.ic2eq + f.a2*f.ic1eq + f.a3*v3
+	f.ic1eq = 2*v1 - f.ic1eq
+	f.ic2eq = 2*v2 - f.ic2eq
+	low = v2
+	band = v1
+	high = x - f.k*v1 - v2
+	notch = low + high
+	return
+}
+
+func (f *SVF) design(cutoff, resonance float32) {
+	f.designed = true
+	f.cutoff, f.resonance = cutoff, resonance
+	fc := clamp(float64(cutoff), 1e-3, .499*float64(f.sampleRate))
+	g := math.Tan(math.Pi * fc / float64(f.sampleRate))
+	k := 2 * (1 - clamp(float64(resonance), 0, 1))
+	a1 := 1 / (1 + g*(g+k))
+	f.k = float32(k)
+	f.a1 = float32(a1)
+	f.a2 = float32(g * a1)
+	f.a3 = float32(g * g * a1)
+}
@@ -0,0 +1,86 @@
+package dsp
+
+import "math"
+
+// Vocoder is a channel vocoder: it splits both carrier and modulator into bands with band-pass filters,
+// and scales each band of the carrier by the envelope of the corresponding band of the modulator.
+// The fields must be set before Init: Bands is the number of bands (0 is taken to be 16), spaced logarithmically
+// from Low to High (in Hz; 0 is taken to be 100 and 8000, respectively).
+// Attack and release are the times in seconds for which the envelope followers rise and fall by 63%.
+// Formant scales the frequencies of the carrier bands relative to those of the modulator (1 for none),
+// which shifts the formants of a voice up or down.
+type Vocoder struct {
+	Bands     int
+	Low, High float32
+
+	sampleRate float32
+	freqs      []float32
+	q          float32
+	modulator  [][2]biquad
+	carrier    [][2]biquad
+	envelopes  []dynamics
+	formant    float32
+}
+
+func (v *Vocoder) Init(c Config) {
+	n, low, high := v.Bands, v.Low, v.High
+	if n <= 0 {
+		n = 16
+	}
+	if low <= 0 {
+		low = 100
+	}
+	if high <= 0 {
+		high = 8000
+	}
+	v.sampleRate = c.SampleRate
+	v.freqs = make([]float32, n)
+	v.modulator = make([][2]biquad, n)
+	v.carrier = make([][2]biquad, n)
+	v.envelopes = make([]dynamics, n)
+	v.formant = 1
+
+	// Each band extends halfway (in log frequency) to its neighbours.
+	r := 2.
+	if n > 1 {
+		r = math.Pow(float64(high/low), 1/float64(n-1))
+	}
+	v.q = float32(math.Sqrt(r) / (r - 1))
+	for i := range v.freqs {
+		v.freqs[i] = low * float32(math.Pow(r, float64(i)))
+		for j := range v.modulator[i] {
+			v.modulator[i][j].design(bandPass, v.sampleRate, v.freqs[i], v.q, 0)
+			v.carrier[i][j].design(bandPass, v.sampleRate, v.freqs[i], v.q, 0)
+		}
+		v.envelopes[i].init(c)
+	}
+}
+
+// vocoderEnvelopeGain corrects the envelopes, which follow the mean of the rectified bands; that of a sinusoid is 2/π of its amplitude.
+const vocoderEnvelopeGain = math.Pi / 2
+
+func (v *Vocoder) Process(carrier, modulator, attack, release, formant float32) float32 {
+	if formant <= 0 {
+		formant = 1
+	}
+	if formant != v.formant {
+		v.formant = formant
+		for i, f := range v.freqs {
+			for j := range v.carrier[i] {
+				v.carrier[i][j].design(bandPass, v.sampleRate, f*formant, v.q, 0)
+			}
+		}
+	}
+
+	y := float32(0)
+	for i := range v.freqs {
+		m, c := modulator, carrier
+		for j := range v.modulator[i] {
+			m = v.modulator[i][j].process(m)
+			c = v.carrier[i][j].process(c)
+		}
+		level := v.envelopes[i].detect(m, attack, release)
+		y += c * vocoderEnvelopeGain * float32(math.Pow(10, float64(level)/20))
+	}
+	return y
+}
@@ -0,0 +1,140 @@
+package wav
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+)
+
+// A Reader reads samples from a WAV stream.
+type Reader struct {
+	Format
+
+	r         io.Reader
+	remaining int64 // bytes of sample data not yet read
+	frames    int64
+	buf       []byte
+}
+
+// NewReader reads the WAV header from r, leaving it positioned at the start of the sample data.
+func NewReader(r io.Reader) (*Reader, error) {
+	var hdr [12]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, err
+	}
+	if string(hdr[:4]) != "RIFF" || string(hdr[8:]) != "WAVE" {
+		return nil, errors.New("wav: not a RIFF/WAVE stream")
+	}
+
+	rd := &Reader{r: r}
+	haveFormat := false
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			if err == io.EOF {
+				err = errors.New("wav: missing data chunk")
+			}
+			return nil, err
+		}
+		size := int64(binary.LittleEndian.Uint32(chunk[4:]))
+		switch string(chunk[:4]) {
+		case "fmt ":
+			if size < 16 || size > 1024 {
+				return nil, errors.New("wav: malformed fmt chunk")
+			}
+			b := make([]byte, size+size%2)
+			if _, err := io.ReadFull(r, b); err != nil {
+				return nil, err
+			}
+			if err := rd.parseFormat(b[:size]); err != nil {
+				return nil, err
+			}
+			haveFormat = true
+		case "data":
+			if !haveFormat {
+				return nil, errors.New("wav: data chunk precedes fmt chunk")
+			}
+			bps := int64(rd.bytesPerSample())
+			rd.remaining = size - size%(bps*int64(rd.Channels))
+			rd.frames = rd.remaining / (bps * int64(rd.Channels))
+			return rd, nil
+		default:
+			if _, err := io.CopyN(ioutil.Discard, r, size+size%2); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func (r *Reader) parseFormat(b []byte) error {
+	le := binary.LittleEndian
+	code := le.Uint16(b[0:])
+	r.Channels = int(le.Uint16(b[2:]))
+	r.SampleRate = int(le.Uint32(b[4:]))
+	r.BitsPerSample = int(le.Uint16(b[14:]))
+	if code == formatExtensible {
+		if len(b) < 40 || string(b[26:40]) != subformatSuffix {
+			return errors.New("wav: malformed WAVE_FORMAT_EXTENSIBLE fmt chunk")
+		}
+		r.ChannelMask = le.Uint32(b[20:])
+		code = le.Uint16(b[24:])
+	}
+	switch code {
+	case formatPCM:
+	case formatFloat:
+		r.Float = true
+	default:
+		return errFormat
+	}
+	return r.Format.validate()
+}
+
+// Len returns the total number of frames (samples per channel) in the stream.
+func (r *Reader) Len() int64 { return r.frames }
+
+// Read reads up to len(samples) interleaved samples, a whole number of frames, and returns the number of samples read.
+// At the end of the data it returns 0, io.EOF; if samples cannot hold a whole frame, it returns 0, io.ErrShortBuffer.
+func (r *Reader) Read(samples []float32) (int, error) {
+	if r.remaining == 0 {
+		return 0, io.EOF
+	}
+	if len(samples) < r.Channels {
+		return 0, io.ErrShortBuffer
+	}
+	bps := r.bytesPerSample()
+	n := len(samples) - len(samples)%r.Channels
+	if max := r.remaining / int64(bps); int64(n) > max {
+		n = int(max)
+	}
+	if cap(r.buf) < n*bps {
+		r.buf = make([]byte, n*bps)
+	}
+	b := r.buf[:n*bps]
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, err
+	}
+	r.remaining -= int64(len(b))
+
+	le := binary.LittleEndian
+	for i := range samples[:n] {
+		s := b[i*bps:]
+		switch {
+		case r.Float && bps == 4:
+			samples[i] = math.Float32frombits(le.Uint32(s))
+		case r.Float:
+			samples[i] = float32(math.Float64frombits(le.Uint64(s)))
+		case bps == 2:
+			samples[i] = float32(int16(le.Uint16(s))) / (1 << 15)
+		case bps == 3:
+			samples[i] = float32(int32(uint32(s[0])<<8|uint32(s[1])<<16|uint32(s[2])<<24)>>8) / (1 << 23)
+		default:
+			samples[i] = float32(float64(int32(le.Uint32(s))) / (1 << 31))
+		}
+	}
+	return n, nil
+}
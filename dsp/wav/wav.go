@@ -0,0 +1,96 @@
+// Package wav reads and writes RIFF/WAVE audio files.
+//
+// Samples are exchanged as interleaved float32 values, nominally in the range -1..1.
+// 16-, 24- and 32-bit integer PCM and 32- and 64-bit floating point data are supported, with any number of channels,
+// in both the plain and the WAVE_FORMAT_EXTENSIBLE variants of the format.
+package wav
+
+import (
+	"errors"
+	"io"
+	"os"
+)
+
+// Format describes the layout of the samples in a WAV file.
+type Format struct {
+	SampleRate    int
+	Channels      int
+	BitsPerSample int  // 16, 24 or 32 for integer PCM; 32 or 64 for floating point.
+	Float         bool // Whether samples are IEEE floating point.
+
+	// ChannelMask assigns speaker positions to channels in a WAVE_FORMAT_EXTENSIBLE file; 0 leaves them unspecified.
+	ChannelMask uint32
+}
+
+const (
+	formatPCM        = 1
+	formatFloat      = 3
+	formatExtensible = 0xFFFE
+)
+
+// subformatSuffix follows the format code in the GUID of a WAVE_FORMAT_EXTENSIBLE subformat.
+const subformatSuffix = "\x00\x00\x00\x00\x10\x00\x80\x00\x00\xaa\x00\x38\x9b\x71"
+
+var errFormat = errors.New("wav: unsupported sample format")
+
+func (f Format) validate() error {
+	if f.Channels < 1 || f.SampleRate < 1 {
+		return errors.New("wav: invalid channel count or sample rate")
+	}
+	switch {
+	case !f.Float && (f.BitsPerSample == 16 || f.BitsPerSample == 24 || f.BitsPerSample == 32):
+	case f.Float && (f.BitsPerSample == 32 || f.BitsPerSample == 64):
+	default:
+		return errFormat
+	}
+	return nil
+}
+
+func (f Format) bytesPerSample() int { return f.BitsPerSample / 8 }
+
+// ReadFile reads all of the samples in the named WAV file.
+func ReadFile(name string) ([]float32, Format, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, Format{}, err
+	}
+	defer file.Close()
+	r, err := NewReader(file)
+	if err != nil {
+		return nil, Format{}, err
+	}
+	samples := make([]float32, 0, r.Len()*int64(r.Channels))
+	buf := make([]float32, 4096*r.Channels)
+	for {
+		n, err := r.Read(buf)
+		samples = append(samples, buf[:n]...)
+		if err == io.EOF {
+			return samples, r.Format, nil
+		}
+		if err != nil {
+			return nil, Format{}, err
+		}
+	}
+}
+
+// WriteFile writes samples to the named WAV file, creating or truncating it.
+func WriteFile(name string, samples []float32, f Format) error {
+	file, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	w, err := NewWriter(file, f)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	if _, err := w.Write(samples); err != nil {
+		file.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
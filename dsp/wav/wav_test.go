@@ -0,0 +1,73 @@
+package wav
+
+import (
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		format Format
+		tol    float64
+	}{
+		{"pcm16", Format{SampleRate: 44100, Channels: 1, BitsPerSample: 16}, 1. / (1 << 15)},
+		{"pcm16 stereo", Format{SampleRate: 48000, Channels: 2, BitsPerSample: 16}, 1. / (1 << 15)},
+		{"pcm24", Format{SampleRate: 48000, Channels: 2, BitsPerSample: 24}, 1. / (1 << 23)},
+		{"pcm32", Format{SampleRate: 96000, Channels: 1, BitsPerSample: 32}, 1e-7},
+		{"float32", Format{SampleRate: 48000, Channels: 3, BitsPerSample: 32, Float: true}, 0},
+		{"float64", Format{SampleRate: 48000, Channels: 1, BitsPerSample: 64, Float: true}, 0},
+		{"extensible", Format{SampleRate: 48000, Channels: 2, BitsPerSample: 16, ChannelMask: 3}, 1. / (1 << 15)},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			samples := make([]float32, 1001*test.format.Channels)
+			for i := range samples {
+				samples[i] = float32(.99 * math.Sin(float64(i)*.1))
+			}
+			name := filepath.Join(t.TempDir(), "test.wav")
+			if err := WriteFile(name, samples, test.format); err != nil {
+				t.Fatal(err)
+			}
+			got, format, err := ReadFile(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format != test.format {
+				t.Errorf("got format %+v, want %+v", format, test.format)
+			}
+			if len(got) != len(samples) {
+				t.Fatalf("got %d samples, want %d", len(got), len(samples))
+			}
+			for i := range got {
+				if d := math.Abs(float64(got[i] - samples[i])); d > test.tol {
+					t.Fatalf("sample %d: got %v, want %v", i, got[i], samples[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReadShortBuffer(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "test.wav")
+	if err := WriteFile(name, make([]float32, 8), Format{SampleRate: 48000, Channels: 2, BitsPerSample: 16}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r, err := NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := r.Read(make([]float32, 1)); n != 0 || err != io.ErrShortBuffer {
+		t.Errorf("got %d, %v; want 0, %v", n, err, io.ErrShortBuffer)
+	}
+	if n, err := r.Read(make([]float32, 3)); n != 2 || err != nil {
+		t.Errorf("got %d, %v; want 2, nil", n, err)
+	}
+}
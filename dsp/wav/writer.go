@@ -0,0 +1,143 @@
+package wav
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+// A Writer writes samples to a WAV stream.
+// The WAVE_FORMAT_EXTENSIBLE variant is used for more than two channels, more than 16 bits per sample, or a non-zero ChannelMask.
+type Writer struct {
+	Format
+
+	w          io.WriteSeeker
+	start      int64 // offset of the RIFF header
+	headerSize int64
+	dataSize   int64
+	buf        []byte
+}
+
+// NewWriter writes a WAV header to w.
+// The header is completed by Close, which is why w must be seekable.
+func NewWriter(w io.WriteSeeker, f Format) (*Writer, error) {
+	if err := f.validate(); err != nil {
+		return nil, err
+	}
+	start, err := w.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	wr := &Writer{Format: f, w: w, start: start}
+	hdr := wr.header()
+	wr.headerSize = int64(len(hdr))
+	if _, err := w.Write(hdr); err != nil {
+		return nil, err
+	}
+	return wr, nil
+}
+
+func (w *Writer) header() []byte {
+	le := binary.LittleEndian
+	extensible := w.Channels > 2 || w.BitsPerSample > 16 || w.ChannelMask != 0
+	code := uint16(formatPCM)
+	if w.Float {
+		code = formatFloat
+	}
+
+	fmtChunk := make([]byte, 16, 40)
+	fmtCode := code
+	if extensible {
+		fmtCode = formatExtensible
+	}
+	blockAlign := w.Channels * w.bytesPerSample()
+	le.PutUint16(fmtChunk[0:], fmtCode)
+	le.PutUint16(fmtChunk[2:], uint16(w.Channels))
+	le.PutUint32(fmtChunk[4:], uint32(w.SampleRate))
+	le.PutUint32(fmtChunk[8:], uint32(w.SampleRate*blockAlign))
+	le.PutUint16(fmtChunk[12:], uint16(blockAlign))
+	le.PutUint16(fmtChunk[14:], uint16(w.BitsPerSample))
+	if extensible {
+		fmtChunk = fmtChunk[:40]
+		le.PutUint16(fmtChunk[16:], 22)
+		le.PutUint16(fmtChunk[18:], uint16(w.BitsPerSample))
+		le.PutUint32(fmtChunk[20:], w.ChannelMask)
+		le.PutUint16(fmtChunk[24:], code)
+		copy(fmtChunk[26:], subformatSuffix)
+	}
+
+	hdr := make([]byte, 0, 12+8+len(fmtChunk)+8)
+	hdr = append(hdr, "RIFF\x00\x00\x00\x00WAVEfmt "...)
+	hdr = appendUint32(hdr, uint32(len(fmtChunk)))
+	hdr = append(hdr, fmtChunk...)
+	hdr = append(hdr, "data\x00\x00\x00\x00"...)
+	le.PutUint32(hdr[4:], uint32(int64(len(hdr))-8+w.dataSize+w.dataSize%2))
+	le.PutUint32(hdr[len(hdr)-4:], uint32(w.dataSize))
+	return hdr
+}
+
+func appendUint32(b []byte, x uint32) []byte {
+	return append(b, byte(x), byte(x>>8), byte(x>>16), byte(x>>24))
+}
+
+// Write writes interleaved samples.
+// Integer samples are clipped to -1..1.
+func (w *Writer) Write(samples []float32) (int, error) {
+	bps := w.bytesPerSample()
+	if cap(w.buf) < len(samples)*bps {
+		w.buf = make([]byte, len(samples)*bps)
+	}
+	b := w.buf[:len(samples)*bps]
+	le := binary.LittleEndian
+	for i, x := range samples {
+		s := b[i*bps:]
+		switch {
+		case w.Float && bps == 4:
+			le.PutUint32(s, math.Float32bits(x))
+		case w.Float:
+			le.PutUint64(s, math.Float64bits(float64(x)))
+		case bps == 2:
+			le.PutUint16(s, uint16(int16(quantize(x, 1<<15))))
+		case bps == 3:
+			v := int32(quantize(x, 1<<23))
+			s[0], s[1], s[2] = byte(v), byte(v>>8), byte(v>>16)
+		default:
+			le.PutUint32(s, uint32(int32(quantize(x, 1<<31))))
+		}
+	}
+	n, err := w.w.Write(b)
+	w.dataSize += int64(n)
+	return n / bps, err
+}
+
+// quantize scales x (clipped to -1..1) to a signed integer with the given full scale.
+func quantize(x float32, fullScale float64) int64 {
+	v := math.Floor(float64(x)*fullScale + .5)
+	return int64(math.Max(-fullScale, math.Min(fullScale-1, v)))
+}
+
+// Close completes the header.
+// It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if w.dataSize > math.MaxUint32-w.headerSize {
+		return errors.New("wav: data too large for a WAV file")
+	}
+	if w.dataSize%2 == 1 {
+		if _, err := w.w.Write([]byte{0}); err != nil {
+			return err
+		}
+	}
+	end, err := w.w.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := w.w.Seek(w.start, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := w.w.Write(w.header()); err != nil {
+		return err
+	}
+	_, err = w.w.Seek(end, io.SeekStart)
+	return err
+}
@@ -0,0 +1,154 @@
+package dsp
+
+import (
+	"math"
+
+	"github.com/gordonklaus/dsp/dsp/fft"
+)
+
+// Wavetable is a wavetable oscillator.
+// Its table holds one or more single-cycle waveforms (frames) of FrameSize samples each.
+// Position (0..1) morphs between the frames by interpolating between neighbouring ones.
+// To avoid aliasing, Init builds a band-limited copy of the table for each octave, and Process plays the one with
+// as many harmonics as fit below the Nyquist frequency.
+//
+// Table and FrameSize may be set, for example by Load, before Init.
+// Otherwise, if File is set, Init loads the table from it, panicking if it cannot be read.
+// If FrameSize is 0, it is taken to be 2048 (the most common size) if that divides the table, and otherwise the table is a single frame.
+// Without a whole frame, it is silent.
+type Wavetable struct {
+	File      string
+	Table     []float32
+	FrameSize int
+
+	phasor
+	sampleRate float32
+	levels     []wavetableLevel
+	freq       float32
+	level      *wavetableLevel
+}
+
+// A wavetableLevel holds the frames of a Wavetable band-limited to a number of harmonics.
+type wavetableLevel struct {
+	harmonics int
+	frames    [][]float32
+}
+
+const (
+	wavetableFrameSize = 2048
+	wavetableMinSize   = 64
+)
+
+// Load reads the table from a WAV file, mixing multiple channels down to mono.
+func (o *Wavetable) Load(name string) error {
+	table, _, err := readWAV(name)
+	if err != nil {
+		return err
+	}
+	o.Table = table
+	return nil
+}
+
+func (o *Wavetable) Init(c Config) {
+	if o.Table == nil && o.File != "" {
+		if err := o.Load(o.File); err != nil {
+			panic(err)
+		}
+	}
+	o.phasor.init(c)
+	o.sampleRate = c.SampleRate
+	o.freq = -1
+	o.level = nil
+	o.levels = nil
+
+	n := o.FrameSize
+	if n <= 0 {
+		n = len(o.Table)
+		if n%wavetableFrameSize == 0 {
+			n = wavetableFrameSize
+		}
+	}
+	if n < 4 || n > len(o.Table) {
+		return
+	}
+	frames := len(o.Table) / n
+	plan := fft.NewReal(n)
+	spectra := make([][]complex64, frames)
+	for i := range spectra {
+		spectra[i] = make([]complex64, n/2+1)
+		plan.Forward(spectra[i], o.Table[i*n:(i+1)*n])
+	}
+
+	// Each level has half the harmonics of the one before, down to a sine.
+	// The tables are resampled to the smallest power of two that oversamples their harmonics by 2 for accurate interpolation,
+	// but no smaller than wavetableMinSize.
+	for h := n/2 - 1; ; h /= 2 {
+		size := wavetableMinSize
+		for size < 4*(h+1) {
+			size *= 2
+		}
+		plan := fft.NewReal(size)
+		spectrum := make([]complex64, size/2+1)
+		scale := complex(float32(size)/float32(n), 0)
+		lv := wavetableLevel{harmonics: h, frames: make([][]float32, frames)}
+		for i, s := range spectra {
+			for k := range spectrum {
+				spectrum[k] = 0
+				if k <= h {
+					spectrum[k] = s[k] * scale
+				}
+			}
+			lv.frames[i] = make([]float32, size)
+			plan.Inverse(lv.frames[i], spectrum)
+		}
+		o.levels = append(o.levels, lv)
+		if h <= 1 {
+			break
+		}
+	}
+}
+
+func (o *Wavetable) Process(freq, position float32) float32 {
+	if len(o.levels) == 0 {
+		return 0
+	}
+	if freq != o.freq {
+		o.freq = freq
+		o.level = o.selectLevel(abs(freq))
+	}
+	t, _ := o.next(freq)
+
+	frames := o.level.frames
+	p := float32(clamp(float64(position), 0, 1)) * float32(len(frames)-1)
+	i := int(p)
+	y := wavetableRead(frames[i], t)
+	if f := p - float32(i); f > 0 {
+		y += f * (wavetableRead(frames[i+1], t) - y)
+	}
+	return y
+}
+
+// selectLevel returns the level with the most harmonics that are all below the Nyquist frequency.
+func (o *Wavetable) selectLevel(freq float32) *wavetableLevel {
+	if freq == 0 {
+		return &o.levels[0]
+	}
+	allowed := int(o.sampleRate / (2 * freq))
+	for i := range o.levels {
+		if o.levels[i].harmonics <= allowed {
+			return &o.levels[i]
+		}
+	}
+	return &o.levels[len(o.levels)-1]
+}
+
+// wavetableRead interpolates the single-cycle table at phase t (0..1).
+func wavetableRead(table []float32, t float32) float32 {
+	n := len(table)
+	i, f := math.Modf(float64(t) * float64(n))
+	j := int(i)
+	at := func(k int) float32 {
+		return table[(k+n)%n]
+	}
+	return interp3(float32(f), at(j-1), at(j), at(j+1), at(j+2))
+}
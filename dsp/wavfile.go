@@ -0,0 +1,19 @@
+package dsp
+
+import "github.com/gordonklaus/dsp/dsp/wav"
+
+// readWAV reads a WAV file, mixing its channels down to mono.
+func readWAV(name string) (samples []float32, sampleRate float32, err error) {
+	data, f, err := wav.ReadFile(name)
+	if err != nil {
+		return nil, 0, err
+	}
+	samples = make([]float32, len(data)/f.Channels)
+	for i := range samples {
+		for _, x := range data[i*f.Channels : (i+1)*f.Channels] {
+			samples[i] += x
+		}
+		samples[i] /= float32(f.Channels)
+	}
+	return samples, float32(f.SampleRate), nil
+}